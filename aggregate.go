@@ -0,0 +1,57 @@
+package health
+
+// Aggregator computes the overall Status of a Service from the Checks collected from its ChecksProviders.
+type Aggregator func(checks map[string][]Checks) Status
+
+// severity orders a Status from healthy to unhealthy.
+func severity(status Status) int {
+	switch status {
+	case Fail:
+		return 2
+	case Warn:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// worse returns the less healthy of two statuses.
+func worse(a Status, b Status) Status {
+	if severity(b) > severity(a) {
+		return b
+	}
+	return a
+}
+
+// Worst is the default Aggregator.
+// The overall Status is Fail if any check fails, Warn if any check warns, and Pass otherwise.
+// Aliases of a Status, like "down" for Fail, count like the Status they stand for.
+// A status which is neither a Status nor an alias, including a missing status, counts as Warn.
+func Worst(checks map[string][]Checks) Status {
+	return NonCritical()(checks)
+}
+
+// NonCritical returns an Aggregator which works like Worst, except that the checks with the given keys are non-critical.
+// A failing non-critical check degrades the overall Status to Warn, but never to Fail.
+func NonCritical(keys ...string) Aggregator {
+	nonCritical := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		nonCritical[key] = true
+	}
+	return func(checks map[string][]Checks) Status {
+		status := Pass
+		for key, checksList := range checks {
+			for _, check := range checksList {
+				checkStatus, err := ParseStatus(string(check.Status))
+				if err != nil {
+					checkStatus = Warn
+				}
+				if nonCritical[key] && checkStatus == Fail {
+					checkStatus = Warn
+				}
+				status = worse(status, checkStatus)
+			}
+		}
+		return status
+	}
+}
@@ -0,0 +1,93 @@
+package health
+
+import (
+	"encoding/json"
+	"github.com/christianhujer/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type staticCheck map[string][]Checks
+
+func (s staticCheck) HealthChecks() map[string][]Checks {
+	return s
+}
+
+func (staticCheck) AuthorizeHealth(*http.Request) bool {
+	return true
+}
+
+func serveHandler(t *testing.T, handler http.HandlerFunc) (*httptest.ResponseRecorder, Health) {
+	r, err := http.NewRequest(http.MethodGet, "/health", nil)
+	if err != nil {
+		panic(err)
+	}
+	w := httptest.NewRecorder()
+	handler(w, r)
+	var h Health
+	if err := json.Unmarshal(w.Body.Bytes(), &h); err != nil {
+		t.Error(err)
+	}
+	return w, h
+}
+
+func serveHealth(t *testing.T, s *Service) (*httptest.ResponseRecorder, Health) {
+	return serveHandler(t, s.Handler)
+}
+
+func TestWorst(t *testing.T) {
+	_ = assert.Equals(t, Pass, Worst(nil))
+	_ = assert.Equals(t, Pass, Worst(map[string][]Checks{"a": {{Status: Pass}}, "b": {{Status: Pass}}}))
+	_ = assert.Equals(t, Warn, Worst(map[string][]Checks{"a": {{Status: Pass}}, "b": {{Status: Warn}}}))
+	_ = assert.Equals(t, Fail, Worst(map[string][]Checks{"a": {{Status: Fail}, {Status: Warn}}, "b": {{Status: Pass}}}))
+}
+
+func TestNonCritical(t *testing.T) {
+	aggregator := NonCritical("SendGrid")
+	_ = assert.Equals(t, Pass, aggregator(map[string][]Checks{"SendGrid": {{Status: Pass}}}))
+	_ = assert.Equals(t, Warn, aggregator(map[string][]Checks{"SendGrid": {{Status: Fail}}}))
+	_ = assert.Equals(t, Warn, aggregator(map[string][]Checks{"SendGrid": {{Status: Warn}}}))
+	_ = assert.Equals(t, Fail, aggregator(map[string][]Checks{"SendGrid": {{Status: Fail}}, "mongodb:responseTime": {{Status: Fail}}}))
+}
+
+func TestHandlerAggregatesStatus(t *testing.T) {
+	failing := staticCheck{"mongodb:responseTime": {{Status: Fail}}}
+	warning := staticCheck{"SendGrid": {{Status: Warn}}}
+
+	_, h := serveHealth(t, New(Health{}, SampleCheck(), warning))
+	_ = assert.Equals(t, Warn, h.Status)
+
+	_, h = serveHealth(t, New(Health{}, SampleCheck(), warning, failing))
+	_ = assert.Equals(t, Fail, h.Status)
+
+	_, h = serveHealth(t, NewWithOptions(Health{}, WithChecksProviders(failing), WithAggregator(NonCritical("mongodb:responseTime"))))
+	_ = assert.Equals(t, Warn, h.Status)
+}
+
+func TestParseStatus(t *testing.T) {
+	for value, expected := range map[string]Status{
+		"pass": Pass, "PASS": Pass, "ok": Pass, "Up": Pass,
+		"warn": Warn, "WARN": Warn,
+		"fail": Fail, "Error": Fail, "DOWN": Fail,
+	} {
+		status, err := ParseStatus(value)
+		_ = assert.Nil(t, err)
+		_ = assert.Equals(t, expected, status)
+	}
+	status, err := ParseStatus("unknown")
+	_ = assert.Equals(t, Status(""), status)
+	_ = assert.Equals(t, `invalid status "unknown"`, err.Error())
+}
+
+func TestWorstWithAliases(t *testing.T) {
+	_ = assert.Equals(t, Fail, Worst(map[string][]Checks{"a": {{Status: "down"}}, "b": {{Status: "ok"}}}))
+	_ = assert.Equals(t, Warn, Worst(map[string][]Checks{"a": {{Status: "WARN"}}, "b": {{Status: "up"}}}))
+}
+
+func TestWorstWithUnknownStatus(t *testing.T) {
+	_ = assert.Equals(t, Warn, Worst(map[string][]Checks{"a": {{Status: "garbage"}}, "b": {{Status: Pass}}}))
+	_ = assert.Equals(t, Warn, Worst(map[string][]Checks{"a": {{}}}))
+	_ = assert.Equals(t, Fail, Worst(map[string][]Checks{"a": {{}}, "b": {{Status: Fail}}}))
+	_ = assert.Equals(t, Warn, NonCritical("a")(map[string][]Checks{"a": {{Status: "garbage"}}}))
+}
@@ -0,0 +1,186 @@
+// Package auth provides reusable authorization for health ChecksProviders.
+// Wrap a ChecksProvider with Protect to replace its own AuthorizeHealth with an Authorizer.
+//
+// Example:
+//
+//	h := health.New(
+//		health.Health{Version: "1", ReleaseID: "1.0.0-SNAPSHOT"},
+//		uptime.System(),
+//		auth.Protect(sysinfo.Health(), auth.BearerToken(os.Getenv("HEALTH_TOKEN"))),
+//	)
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"github.com/nelkinda/health-go"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Authorizer decides whether a request is authorized to see the details of health Checks.
+type Authorizer func(r *http.Request) bool
+
+type protected struct {
+	checksProvider health.ChecksProvider
+	authorizer     Authorizer
+}
+
+func (p *protected) HealthChecks() map[string][]health.Checks {
+	return p.checksProvider.HealthChecks()
+}
+
+func (p *protected) HealthChecksContext(ctx context.Context) map[string][]health.Checks {
+	return health.AdaptChecksProvider(p.checksProvider).HealthChecksContext(ctx)
+}
+
+func (p *protected) AuthorizeHealth(r *http.Request) bool {
+	return p.authorizer(r)
+}
+
+// protectedPoller is a protected ChecksProvider which is a health.Poller because the protected ChecksProvider is one.
+type protectedPoller struct {
+	protected
+	poller health.Poller
+}
+
+func (p *protectedPoller) Start(ctx context.Context) {
+	p.poller.Start(ctx)
+}
+
+func (p *protectedPoller) Stop() {
+	p.poller.Stop()
+}
+
+// Protect returns a ChecksProvider which provides the Checks of checksProvider, authorized by authorizer.
+// The returned ChecksProvider is a health.Poller if and only if checksProvider is one.
+func Protect(checksProvider health.ChecksProvider, authorizer Authorizer) health.ChecksProvider {
+	p := protected{checksProvider: checksProvider, authorizer: authorizer}
+	if poller, ok := checksProvider.(health.Poller); ok {
+		return &protectedPoller{protected: p, poller: poller}
+	}
+	return &p
+}
+
+// Any returns an Authorizer which authorizes a request if any of the given authorizers authorizes it.
+func Any(authorizers ...Authorizer) Authorizer {
+	return func(r *http.Request) bool {
+		for _, authorizer := range authorizers {
+			if authorizer(r) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// All returns an Authorizer which authorizes a request if all of the given authorizers authorize it.
+func All(authorizers ...Authorizer) Authorizer {
+	return func(r *http.Request) bool {
+		for _, authorizer := range authorizers {
+			if !authorizer(r) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// equal compares two secrets in constant time.
+func equal(a string, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// BearerToken returns an Authorizer which authorizes requests with an "Authorization: Bearer" header carrying one of the given tokens.
+func BearerToken(tokens ...string) Authorizer {
+	return func(r *http.Request) bool {
+		const prefix = "Bearer "
+		authorization := r.Header.Get("Authorization")
+		if len(authorization) < len(prefix) || !strings.EqualFold(authorization[:len(prefix)], prefix) {
+			return false
+		}
+		token := authorization[len(prefix):]
+		authorized := false
+		for _, expected := range tokens {
+			if expected != "" && equal(token, expected) {
+				authorized = true
+			}
+		}
+		return authorized
+	}
+}
+
+// BasicAuth returns an Authorizer which authorizes requests with HTTP Basic Authentication credentials matching username and password.
+func BasicAuth(username string, password string) Authorizer {
+	return func(r *http.Request) bool {
+		actualUsername, actualPassword, ok := r.BasicAuth()
+		if !ok {
+			return false
+		}
+		usernameMatches := equal(actualUsername, username)
+		passwordMatches := equal(actualPassword, password)
+		return usernameMatches && passwordMatches
+	}
+}
+
+// SourceCIDR returns an Authorizer which authorizes requests whose remote address lies within one of the given CIDR ranges, like "10.0.0.0/8" or "::1/128".
+// Only the remote address of the connection is considered, headers like X-Forwarded-For are ignored.
+func SourceCIDR(cidrs ...string) (Authorizer, error) {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, network)
+	}
+	return func(r *http.Request) bool {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+		ip := net.ParseIP(host)
+		if ip == nil {
+			return false
+		}
+		for _, network := range networks {
+			if network.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// MustSourceCIDR is like SourceCIDR but panics if a CIDR range cannot be parsed.
+func MustSourceCIDR(cidrs ...string) Authorizer {
+	authorizer, err := SourceCIDR(cidrs...)
+	if err != nil {
+		panic(err)
+	}
+	return authorizer
+}
+
+// ClientCertificateSubject returns an Authorizer which authorizes requests made with a verified TLS client certificate whose subject matches one of the given subjects.
+// A subject matches either the subject's common name, like "monitoring", or its full distinguished name, like "CN=monitoring,O=Nelkinda".
+// The server must be configured to verify client certificates, for example with tls.RequireAndVerifyClientCert.
+func ClientCertificateSubject(subjects ...string) Authorizer {
+	return func(r *http.Request) bool {
+		if r.TLS == nil {
+			return false
+		}
+		for _, chain := range r.TLS.VerifiedChains {
+			if len(chain) == 0 {
+				continue
+			}
+			subject := chain[0].Subject
+			for _, expected := range subjects {
+				if expected == subject.CommonName || expected == subject.String() {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,184 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"github.com/christianhujer/assert"
+	"github.com/nelkinda/health-go"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type public struct{}
+
+func (*public) HealthChecks() map[string][]health.Checks {
+	return map[string][]health.Checks{"public": {{Status: health.Pass}}}
+}
+
+func (*public) AuthorizeHealth(*http.Request) bool {
+	return true
+}
+
+func request() *http.Request {
+	return httptest.NewRequest(http.MethodGet, "/health", nil)
+}
+
+func TestProtect(t *testing.T) {
+	checksProvider := Protect(&public{}, BearerToken("secret"))
+	_ = assert.DeepEquals(t, map[string][]health.Checks{"public": {{Status: health.Pass}}}, checksProvider.HealthChecks())
+	_ = assert.DeepEquals(t, map[string][]health.Checks{"public": {{Status: health.Pass}}}, health.AdaptChecksProvider(checksProvider).HealthChecksContext(context.Background()))
+	_ = assert.False(t, checksProvider.AuthorizeHealth(request()))
+	r := request()
+	r.Header.Set("Authorization", "Bearer secret")
+	_ = assert.True(t, checksProvider.AuthorizeHealth(r))
+}
+
+type counting struct {
+	readings int32
+}
+
+func (c *counting) HealthChecks() map[string][]health.Checks {
+	atomic.AddInt32(&c.readings, 1)
+	return map[string][]health.Checks{"counting": {{Status: health.Pass}}}
+}
+
+func (*counting) AuthorizeHealth(*http.Request) bool {
+	return true
+}
+
+func TestProtectPoller(t *testing.T) {
+	c := &counting{}
+	checksProvider := Protect(health.Cached(c, time.Millisecond), BearerToken("secret")).(health.Poller)
+	checksProvider.Start(context.Background())
+	for atomic.LoadInt32(&c.readings) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	checksProvider.Stop()
+	stopped := atomic.LoadInt32(&c.readings)
+	time.Sleep(10 * time.Millisecond)
+	_ = assert.Equals(t, stopped, atomic.LoadInt32(&c.readings))
+
+	_, isPoller := Protect(c, BearerToken("secret")).(health.Poller)
+	_ = assert.False(t, isPoller)
+}
+
+func TestProtectWithPollInterval(t *testing.T) {
+	c := &counting{}
+	h := health.NewWithOptions(
+		health.Health{Version: "1"},
+		health.WithChecksProviders(Protect(c, BearerToken("secret"))),
+		health.WithPollInterval(time.Hour),
+	)
+	h.Start(context.Background())
+	defer h.Stop()
+	for deadline := time.Now().Add(time.Second); atomic.LoadInt32(&c.readings) < 1 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+
+	for i := 0; i < 3; i++ {
+		response := httptest.NewRecorder()
+		h.Handler(response, request())
+		_ = assert.Equals(t, http.StatusOK, response.Code)
+	}
+
+	_ = assert.Equals(t, int32(1), atomic.LoadInt32(&c.readings))
+}
+
+func TestBearerToken(t *testing.T) {
+	authorizer := BearerToken("secret", "other")
+	for _, tc := range []struct {
+		authorization string
+		expected      bool
+	}{
+		{"", false},
+		{"Bearer", false},
+		{"Bearer wrong", false},
+		{"Basic secret", false},
+		{"Bearer secret", true},
+		{"bearer other", true},
+	} {
+		r := request()
+		r.Header.Set("Authorization", tc.authorization)
+		_ = assert.Equals(t, tc.expected, authorizer(r))
+	}
+	r := request()
+	r.Header.Set("Authorization", "Bearer ")
+	_ = assert.False(t, BearerToken("")(r))
+}
+
+func TestBasicAuth(t *testing.T) {
+	authorizer := BasicAuth("monitor", "secret")
+	r := request()
+	_ = assert.False(t, authorizer(r))
+	r.SetBasicAuth("monitor", "wrong")
+	_ = assert.False(t, authorizer(r))
+	r.SetBasicAuth("intruder", "secret")
+	_ = assert.False(t, authorizer(r))
+	r.SetBasicAuth("monitor", "secret")
+	_ = assert.True(t, authorizer(r))
+}
+
+func TestSourceCIDR(t *testing.T) {
+	authorizer := MustSourceCIDR("10.0.0.0/8", "::1/128")
+	for _, tc := range []struct {
+		remoteAddr string
+		expected   bool
+	}{
+		{"10.1.2.3:4567", true},
+		{"10.1.2.3", true},
+		{"[::1]:4567", true},
+		{"192.168.0.1:4567", false},
+		{"not an address", false},
+	} {
+		r := request()
+		r.RemoteAddr = tc.remoteAddr
+		_ = assert.Equals(t, tc.expected, authorizer(r))
+	}
+}
+
+func TestSourceCIDRInvalid(t *testing.T) {
+	authorizer, err := SourceCIDR("10.0.0.0/33")
+	_ = assert.True(t, authorizer == nil)
+	_ = assert.NotNil(t, err)
+	defer func() {
+		_ = assert.NotNil(t, recover())
+	}()
+	MustSourceCIDR("invalid")
+}
+
+func TestClientCertificateSubject(t *testing.T) {
+	authorizer := ClientCertificateSubject("monitoring", "CN=prometheus,O=Nelkinda")
+	certificate := func(name pkix.Name) *tls.ConnectionState {
+		return &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{}, {{Subject: name}}}}
+	}
+	for _, tc := range []struct {
+		state    *tls.ConnectionState
+		expected bool
+	}{
+		{nil, false},
+		{&tls.ConnectionState{}, false},
+		{certificate(pkix.Name{CommonName: "monitoring"}), true},
+		{certificate(pkix.Name{CommonName: "prometheus", Organization: []string{"Nelkinda"}}), true},
+		{certificate(pkix.Name{CommonName: "prometheus"}), false},
+	} {
+		r := request()
+		r.TLS = tc.state
+		_ = assert.Equals(t, tc.expected, authorizer(r))
+	}
+}
+
+func TestAnyAll(t *testing.T) {
+	yes := func(*http.Request) bool { return true }
+	no := func(*http.Request) bool { return false }
+	_ = assert.True(t, Any(no, yes)(request()))
+	_ = assert.False(t, Any(no, no)(request()))
+	_ = assert.False(t, Any()(request()))
+	_ = assert.True(t, All(yes, yes)(request()))
+	_ = assert.False(t, All(yes, no)(request()))
+	_ = assert.True(t, All()(request()))
+}
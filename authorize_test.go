@@ -0,0 +1,46 @@
+package health
+
+import (
+	"github.com/christianhujer/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type secretCheck map[string][]Checks
+
+func (s secretCheck) HealthChecks() map[string][]Checks {
+	return s
+}
+
+func (secretCheck) AuthorizeHealth(r *http.Request) bool {
+	return r.Header.Get("Authorization") == "Bearer secret"
+}
+
+func TestHandlerDropsUnauthorizedChecks(t *testing.T) {
+	secret := secretCheck{"hostname": {{ComponentID: "hostname", ObservedValue: "secret-host", Status: Fail}}}
+	w, h := serveHealth(t, New(Health{}, SampleCheck(), secret))
+	_ = assert.Equals(t, http.StatusServiceUnavailable, w.Code)
+	_ = assert.Equals(t, Fail, h.Status)
+	_ = assert.Equals(t, 1, len(h.Checks))
+	_ = assert.Equals(t, 1, len(h.Checks["sampleCheck"]))
+}
+
+func TestHandlerRedactsUnauthorizedChecks(t *testing.T) {
+	secret := secretCheck{"hostname": {{ComponentID: "hostname", ObservedValue: "secret-host", Status: Pass}}}
+	_, h := serveHealth(t, NewWithOptions(Health{}, WithChecksProviders(secret), WithUnauthorized(Redact)))
+	_ = assert.DeepEquals(t, map[string][]Checks{"hostname": {{Status: Pass}}}, h.Checks)
+}
+
+func TestHandlerIncludesAuthorizedChecks(t *testing.T) {
+	secret := secretCheck{"hostname": {{ComponentID: "hostname", ObservedValue: "secret-host", Status: Pass}}}
+	r, err := http.NewRequest(http.MethodGet, "/health", nil)
+	if err != nil {
+		panic(err)
+	}
+	r.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	New(Health{}, secret).Handler(w, r)
+	_ = assert.True(t, strings.Contains(w.Body.String(), "secret-host"))
+}
@@ -0,0 +1,177 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Poller is implemented by ChecksProviders which refresh their Checks in the background.
+// Service.Start and Service.Stop start and stop all registered Pollers.
+type Poller interface {
+	// Start starts refreshing in the background until ctx is done or Stop is called.
+	Start(ctx context.Context)
+
+	// Stop stops refreshing and waits for a running refresh to finish.
+	Stop()
+}
+
+type cached struct {
+	// The provider which is read, with the timeout of each reading.
+	source   *registration
+	timeout  time.Duration
+	interval time.Duration
+	// Guards checks, readAt, cancel and done.
+	mutex  sync.Mutex
+	checks map[string][]Checks
+	readAt time.Time
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Cached returns a ChecksProvider which serves the most recent Checks of checksProvider instead of asking it on every request.
+// After Start, the Checks are refreshed in the background every interval.
+// Until the first reading is available, and while not polling, like before Start or after Stop,
+// the Checks are read on request if the most recent reading is older than interval.
+// The Time of each Checks entry reflects when it was read, not when it was served.
+func Cached(checksProvider ChecksProvider, interval time.Duration) ChecksProvider {
+	return newCached(newRegistration(checksProvider, AdaptChecksProvider(checksProvider)), interval, 0)
+}
+
+// newCached returns a cache for source.
+// With a timeout greater than 0, a reading which takes longer is reported with failed checks, like in Service.Handler.
+func newCached(source *registration, interval time.Duration, timeout time.Duration) *cached {
+	return &cached{source: source, timeout: timeout, interval: interval}
+}
+
+func (c *cached) HealthChecks() map[string][]Checks {
+	return c.HealthChecksContext(context.Background())
+}
+
+func (c *cached) HealthChecksContext(ctx context.Context) map[string][]Checks {
+	c.mutex.Lock()
+	checks := c.checks
+	stale := checks == nil || !c.polling() && time.Since(c.readAt) >= c.interval
+	c.mutex.Unlock()
+	if stale {
+		checks = c.refresh(ctx)
+	}
+	return copyChecks(checks)
+}
+
+// polling returns whether the Checks are refreshed in the background.
+// The caller must hold the mutex.
+func (c *cached) polling() bool {
+	return c.done != nil
+}
+
+func (c *cached) AuthorizeHealth(r *http.Request) bool {
+	return c.source.checksProvider.AuthorizeHealth(r)
+}
+
+// refresh reads the checks from the provider and stores them as the most recent reading.
+// With a timeout, it returns when the timeout expires or ctx is done, even if the provider does not.
+func (c *cached) refresh(ctx context.Context) map[string][]Checks {
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	var checks map[string][]Checks
+	if c.timeout > 0 {
+		checks = copyChecks(c.source.healthChecks(ctx, c.timeout))
+	} else {
+		checks = copyChecks(c.source.checksProvider.HealthChecksContext(ctx))
+	}
+	for _, checksList := range checks {
+		for i := range checksList {
+			if checksList[i].Time == "" {
+				checksList[i].Time = now
+			}
+		}
+	}
+	if ctx.Err() != nil {
+		// An interrupted reading is not worth keeping.
+		return checks
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.checks = checks
+	c.readAt = time.Now()
+	return checks
+}
+
+func (c *cached) Start(ctx context.Context) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.polling() {
+		return
+	}
+	ctx, c.cancel = context.WithCancel(ctx)
+	c.done = make(chan struct{})
+	go c.poll(ctx, c.done)
+}
+
+func (c *cached) poll(ctx context.Context, done chan struct{}) {
+	defer close(done)
+	defer c.finished(done)
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		c.refresh(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// finished forgets the poll loop which closes done, unless it was stopped or replaced already, so that the cache can be started again.
+func (c *cached) finished(done chan struct{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.done == done {
+		c.cancel()
+		c.cancel, c.done = nil, nil
+	}
+}
+
+func (c *cached) Stop() {
+	c.mutex.Lock()
+	cancel, done := c.cancel, c.done
+	c.cancel, c.done = nil, nil
+	c.mutex.Unlock()
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+// copyChecks returns a copy of checks which can be modified without affecting the original.
+func copyChecks(checks map[string][]Checks) map[string][]Checks {
+	if checks == nil {
+		return make(map[string][]Checks)
+	}
+	copied := make(map[string][]Checks, len(checks))
+	for key, checksList := range checks {
+		copied[key] = append([]Checks(nil), checksList...)
+	}
+	return copied
+}
+
+// Start starts all registered Pollers, like the ChecksProviders returned by Cached.
+// They refresh in the background until ctx is done or Stop is called.
+func (h *Service) Start(ctx context.Context) {
+	for _, checksProvider := range h.checksProviders {
+		if poller, ok := checksProvider.checksProvider.(Poller); ok {
+			poller.Start(ctx)
+		}
+	}
+}
+
+// Stop stops all registered Pollers.
+func (h *Service) Stop() {
+	for _, checksProvider := range h.checksProviders {
+		if poller, ok := checksProvider.checksProvider.(Poller); ok {
+			poller.Stop()
+		}
+	}
+}
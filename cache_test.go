@@ -0,0 +1,188 @@
+package health
+
+import (
+	"context"
+	"github.com/christianhujer/assert"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+type countingCheck struct {
+	mutex sync.Mutex
+	count int
+}
+
+func (c *countingCheck) HealthChecks() map[string][]Checks {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.count++
+	return map[string][]Checks{"counting": {{ObservedValue: c.count, Status: Pass}}}
+}
+
+func (*countingCheck) AuthorizeHealth(*http.Request) bool {
+	return true
+}
+
+func (c *countingCheck) readings() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.count
+}
+
+func eventually(condition func() bool) bool {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if condition() {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCachedReadsOnceBeforeStart(t *testing.T) {
+	counting := &countingCheck{}
+	c := Cached(counting, time.Hour)
+	first := c.HealthChecks()
+	second := c.HealthChecks()
+	_ = assert.Equals(t, 1, counting.readings())
+	_ = assert.Equals(t, first["counting"][0].Time, second["counting"][0].Time)
+	_ = assert.True(t, first["counting"][0].Time != "")
+	_ = assert.True(t, c.AuthorizeHealth(nil))
+}
+
+func TestCachedRefreshesStaleReadingWhileNotPolling(t *testing.T) {
+	counting := &countingCheck{}
+	c := Cached(counting, 5*time.Millisecond)
+	_ = assert.Equals(t, 1, c.HealthChecks()["counting"][0].ObservedValue)
+	time.Sleep(10 * time.Millisecond)
+	_ = assert.Equals(t, 2, c.HealthChecks()["counting"][0].ObservedValue)
+}
+
+func TestCachedRefreshesStaleReadingAfterStop(t *testing.T) {
+	counting := &countingCheck{}
+	c := Cached(counting, 5*time.Millisecond)
+	c.(Poller).Start(context.Background())
+	_ = assert.True(t, eventually(func() bool { return counting.readings() >= 1 }))
+	c.(Poller).Stop()
+	stopped := counting.readings()
+	time.Sleep(10 * time.Millisecond)
+	_ = assert.Equals(t, stopped+1, c.HealthChecks()["counting"][0].ObservedValue)
+}
+
+func TestCachedKeepsTimeOfReading(t *testing.T) {
+	c := Cached(staticCheck{"static": {{Status: Pass, Time: "2020-03-08T16:48:01Z"}}}, time.Hour)
+	_ = assert.Equals(t, "2020-03-08T16:48:01Z", c.HealthChecks()["static"][0].Time)
+}
+
+func TestCachedReturnsCopies(t *testing.T) {
+	c := Cached(staticCheck{"static": {{Status: Pass}}}, time.Hour)
+	c.HealthChecks()["static"][0].Status = Fail
+	_ = assert.Equals(t, Pass, c.HealthChecks()["static"][0].Status)
+	_ = assert.Equals(t, 0, len(Cached(staticCheck(nil), time.Hour).HealthChecks()))
+}
+
+func TestCachedDiscardsInterruptedReading(t *testing.T) {
+	counting := &countingCheck{}
+	c := Cached(counting, time.Hour).(*cached)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c.HealthChecksContext(ctx)
+	c.HealthChecks()
+	_ = assert.Equals(t, 2, counting.readings())
+}
+
+func TestCachedPolls(t *testing.T) {
+	counting := &countingCheck{}
+	c := Cached(counting, time.Millisecond).(Poller)
+	c.Start(context.Background())
+	c.Start(context.Background())
+	_ = assert.True(t, eventually(func() bool { return counting.readings() >= 3 }))
+	c.Stop()
+	stopped := counting.readings()
+	time.Sleep(10 * time.Millisecond)
+	_ = assert.Equals(t, stopped, counting.readings())
+	c.Stop()
+}
+
+func TestCachedStopsWithContext(t *testing.T) {
+	counting := &countingCheck{}
+	ctx, cancel := context.WithCancel(context.Background())
+	c := Cached(counting, time.Millisecond).(*cached)
+	c.Start(ctx)
+	c.mutex.Lock()
+	done := c.done
+	c.mutex.Unlock()
+	cancel()
+	<-done
+	c.mutex.Lock()
+	_ = assert.False(t, c.polling())
+	c.mutex.Unlock()
+	c.Stop()
+
+	c.Start(context.Background())
+	defer c.Stop()
+	readings := counting.readings()
+	_ = assert.True(t, eventually(func() bool { return counting.readings() > readings+1 }))
+}
+
+// stuckCheck is a legacy provider which ignores the context and does not return until released.
+type stuckCheck struct {
+	release chan struct{}
+}
+
+func (c stuckCheck) HealthChecks() map[string][]Checks {
+	<-c.release
+	return map[string][]Checks{"stuck": {{Status: Pass}}}
+}
+
+func (stuckCheck) AuthorizeHealth(*http.Request) bool {
+	return true
+}
+
+func TestServicePollIntervalWithTimeout(t *testing.T) {
+	stuck := stuckCheck{release: make(chan struct{})}
+	defer close(stuck.release)
+	s := NewWithOptions(Health{}, WithChecksProviders(stuck), WithPollInterval(time.Hour), WithTimeout(10*time.Millisecond))
+	s.Start(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		_ = assert.True(t, eventually(func() bool {
+			c := s.checksProviders[0].checksProvider.(*cached)
+			c.mutex.Lock()
+			defer c.mutex.Unlock()
+			return c.checks != nil
+		}))
+		s.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not return")
+	}
+	_, h := serveHealth(t, s)
+	_ = assert.Equals(t, Fail, h.Status)
+	_ = assert.Equals(t, "timeout after 10ms: context deadline exceeded", h.Checks["health.stuckCheck"][0].Output)
+}
+
+func TestServicePollInterval(t *testing.T) {
+	counting := &countingCheck{}
+	s := NewWithOptions(Health{}, WithChecksProviders(counting, Cached(SampleCheck(), time.Hour)), WithPollInterval(time.Millisecond))
+	s.Start(context.Background())
+	defer s.Stop()
+	_ = assert.True(t, eventually(func() bool { return counting.readings() >= 3 }))
+	readings := counting.readings()
+	_, h := serveHealth(t, s)
+	_ = assert.Equals(t, Pass, h.Status)
+	_ = assert.True(t, h.Checks["counting"][0].ObservedValue.(float64) >= float64(readings))
+	_ = assert.Equals(t, 1, len(h.Checks["sampleCheck"]))
+}
+
+func TestServiceStartStopWithoutPollers(t *testing.T) {
+	s := New(Health{}, SampleCheck())
+	s.Start(context.Background())
+	s.Stop()
+	_, h := serveHealth(t, s)
+	_ = assert.Equals(t, Pass, h.Status)
+}
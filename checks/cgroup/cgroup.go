@@ -0,0 +1,311 @@
+// Package cgroup provides health checks for the resource limits of a container, from its control group (cgroup).
+// Both cgroup v1 and cgroup v2 are supported.
+// Thresholds of 0 turn the corresponding warning or failure off.
+package cgroup
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/nelkinda/health-go"
+	"github.com/nelkinda/health-go/internal/threshold"
+	"github.com/nelkinda/health-go/internal/window"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRoot is the path where the cgroup file system is usually mounted.
+const DefaultRoot = "/sys/fs/cgroup"
+
+// unlimited is the value from which on a cgroup v1 limit means no limit.
+// The kernel reports no limit as the largest multiple of the page size.
+const unlimited = 1 << 62
+
+// files are the paths of the cgroup files of a cgroup version, relative to the root.
+type files struct {
+	version       string
+	memoryCurrent string
+	memoryMax     string
+	cpuStat       string
+	throttledTime string
+	// throttledUnit is the duration of a unit of throttledTime.
+	throttledUnit time.Duration
+	pidsCurrent   string
+	pidsMax       string
+	memoryEvents  string
+}
+
+var v2 = files{
+	version:       "v2",
+	memoryCurrent: "memory.current",
+	memoryMax:     "memory.max",
+	cpuStat:       "cpu.stat",
+	throttledTime: "throttled_usec",
+	throttledUnit: time.Microsecond,
+	pidsCurrent:   "pids.current",
+	pidsMax:       "pids.max",
+	memoryEvents:  "memory.events",
+}
+
+var v1 = files{
+	version:       "v1",
+	memoryCurrent: "memory/memory.usage_in_bytes",
+	memoryMax:     "memory/memory.limit_in_bytes",
+	cpuStat:       "cpu/cpu.stat",
+	throttledTime: "throttled_time",
+	throttledUnit: time.Nanosecond,
+	pidsCurrent:   "pids/pids.current",
+	pidsMax:       "pids/pids.max",
+	memoryEvents:  "memory/memory.oom_control",
+}
+
+type cgroup struct {
+	root           string
+	memoryWarn     float64
+	memoryFail     float64
+	throttlingWarn float64
+	throttlingFail float64
+	pidsWarn       float64
+	pidsFail       float64
+	window         time.Duration
+	clock          func() time.Time
+	// Guards the counters.
+	mutex     sync.Mutex
+	periods   *window.Counter
+	throttled *window.Counter
+	oomKills  *window.Counter
+}
+
+// Option configures a cgroup health check.
+type Option func(*cgroup)
+
+// WithRoot sets the path of the cgroup file system, DefaultRoot by default.
+func WithRoot(root string) Option {
+	return func(c *cgroup) {
+		c.root = root
+	}
+}
+
+// DefaultWindow is the default time window for CPU throttling and OOM kills.
+const DefaultWindow = 5 * time.Minute
+
+// WithWindow sets the time window for CPU throttling and OOM kills, DefaultWindow by default.
+// Throttling is measured over the window, and an OOM kill warns until the window has passed.
+// This way, all readers of the check see the same result, however often the check is read.
+func WithWindow(window time.Duration) Option {
+	return func(c *cgroup) {
+		c.window = window
+	}
+}
+
+// WithMemoryThresholds sets the memory utilization in percent of the limit above which the check warns or fails.
+// The defaults are 80 and 90.
+func WithMemoryThresholds(warn float64, fail float64) Option {
+	return func(c *cgroup) {
+		c.memoryWarn = warn
+		c.memoryFail = fail
+	}
+}
+
+// WithThrottlingThresholds sets the percentage of throttled CPU periods above which the check warns or fails.
+// By default, throttling is reported without thresholds.
+func WithThrottlingThresholds(warn float64, fail float64) Option {
+	return func(c *cgroup) {
+		c.throttlingWarn = warn
+		c.throttlingFail = fail
+	}
+}
+
+// WithPIDsThresholds sets the number of processes in percent of the limit above which the check warns or fails.
+// The defaults are 80 and 90.
+func WithPIDsThresholds(warn float64, fail float64) Option {
+	return func(c *cgroup) {
+		c.pidsWarn = warn
+		c.pidsFail = fail
+	}
+}
+
+// readValue reads a file with a single value, like memory.max.
+// The value "max" is returned as 0.
+// A file that does not exist, because its controller is not enabled, is returned as not ok, without error.
+func readValue(path string) (value uint64, ok bool, err error) {
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	text := strings.TrimSpace(string(content))
+	if text == "max" {
+		return 0, true, nil
+	}
+	value, err = strconv.ParseUint(text, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("cgroup: %s: %v", path, err)
+	}
+	if value >= unlimited {
+		value = 0
+	}
+	return value, true, nil
+}
+
+// readKeyValues reads a file with lines of keys and values, like cpu.stat.
+// A file that does not exist is returned as not ok, without error.
+func readKeyValues(path string) (values map[string]uint64, ok bool, err error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() { _ = file.Close() }()
+	values = make(map[string]uint64)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, false, fmt.Errorf("cgroup: %s: %v", path, err)
+		}
+		values[fields[0]] = value
+	}
+	return values, true, scanner.Err()
+}
+
+// detect returns the files of the cgroup version mounted at root.
+func (c *cgroup) detect() (files, error) {
+	if _, err := os.Stat(filepath.Join(c.root, "cgroup.controllers")); err == nil {
+		return v2, nil
+	}
+	if _, err := os.Stat(filepath.Join(c.root, "memory")); err == nil {
+		return v1, nil
+	}
+	return files{}, errors.New("cgroup: no cgroup v1 or v2 found at " + c.root)
+}
+
+func (c *cgroup) HealthChecks() map[string][]health.Checks {
+	readAt := c.clock()
+	now := readAt.UTC().Format(time.RFC3339Nano)
+	result := make(map[string][]health.Checks)
+	observe := func(key string, componentID string, value interface{}, unit string, status health.Status) {
+		result[key] = append(result[key], health.Checks{
+			ComponentID:   componentID,
+			ComponentType: "system",
+			ObservedValue: value,
+			ObservedUnit:  unit,
+			Status:        status,
+			Time:          now,
+		})
+	}
+	failed := func(key string, err error) {
+		result[key] = append(result[key], health.Checks{
+			ComponentType: "system",
+			Status:        health.Fail,
+			Output:        err.Error(),
+			Time:          now,
+		})
+	}
+
+	f, err := c.detect()
+	if err != nil {
+		failed("cgroup:version", err)
+		return result
+	}
+	observe("cgroup:version", "", f.version, "", health.Pass)
+	path := func(name string) string {
+		return filepath.Join(c.root, name)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := c.limited("cgroup:memory", path(f.memoryCurrent), path(f.memoryMax), "bytes", c.memoryWarn, c.memoryFail, observe); err != nil {
+		failed("cgroup:memory", err)
+	}
+	if err := c.limited("cgroup:pids", path(f.pidsCurrent), path(f.pidsMax), "", c.pidsWarn, c.pidsFail, observe); err != nil {
+		failed("cgroup:pids", err)
+	}
+
+	if stat, ok, err := readKeyValues(path(f.cpuStat)); err != nil {
+		failed("cgroup:cpu", err)
+	} else if ok {
+		periods := c.periods.Observe(readAt, stat["nr_periods"])
+		throttled := c.throttled.Observe(readAt, stat["nr_throttled"])
+		var throttling float64
+		if periods > 0 {
+			throttling = float64(throttled) * 100 / float64(periods)
+		}
+		observe("cgroup:cpu", "throttled periods", throttling, "%", threshold.Above(throttling, c.throttlingWarn, c.throttlingFail))
+		observe("cgroup:cpu", "throttled time", (time.Duration(stat[f.throttledTime]) * f.throttledUnit).Nanoseconds(), "ns", health.Pass)
+	}
+
+	if events, ok, err := readKeyValues(path(f.memoryEvents)); err != nil {
+		failed("cgroup:oomKills", err)
+	} else if ok {
+		oomKills := events["oom_kill"]
+		observe("cgroup:oomKills", "", oomKills, "", health.Pass)
+		if recent := c.oomKills.Observe(readAt, oomKills); recent > 0 {
+			check := &result["cgroup:oomKills"][0]
+			check.Status = health.Warn
+			check.Output = fmt.Sprintf("%d processes killed within %v", recent, c.window)
+		}
+	}
+	return result
+}
+
+// limited observes the usage of a resource against its limit, if the resource controller is enabled.
+func (c *cgroup) limited(key string, currentPath string, maxPath string, unit string, warn float64, fail float64, observe func(string, string, interface{}, string, health.Status)) error {
+	current, ok, err := readValue(currentPath)
+	if err != nil || !ok {
+		return err
+	}
+	max, _, err := readValue(maxPath)
+	if err != nil {
+		return err
+	}
+	observe(key, "current", current, unit, health.Pass)
+	if max == 0 {
+		return nil
+	}
+	observe(key, "max", max, unit, health.Pass)
+	utilization := float64(current) * 100 / float64(max)
+	observe(key, "utilization", utilization, "%", threshold.Above(utilization, warn, fail))
+	return nil
+}
+
+func (*cgroup) AuthorizeHealth(*http.Request) bool {
+	return true
+}
+
+// Health returns a ChecksProvider for the resources of the cgroup mounted at DefaultRoot, or WithRoot.
+// Inside a container, this is the cgroup of the container.
+//
+// The cgroup version is reported under "cgroup:version".
+// Under "cgroup:memory" and "cgroup:pids", the componentIds "current" and "max" report the usage and its limit,
+// and "utilization" reports the usage in percent of the limit, if there is a limit.
+// Under "cgroup:cpu", "throttled periods" reports the percentage of CPU periods in which the cgroup was throttled within the window, see WithWindow,
+// and "throttled time" reports the total time throttled.
+// Under "cgroup:oomKills", the number of processes killed because the cgroup ran out of memory is reported.
+// It warns if processes were killed within the window.
+// Resources whose controller is not enabled are not reported.
+func Health(options ...Option) health.ChecksProvider {
+	c := &cgroup{root: DefaultRoot, memoryWarn: 80, memoryFail: 90, pidsWarn: 80, pidsFail: 90, window: DefaultWindow, clock: time.Now}
+	for _, option := range options {
+		option(c)
+	}
+	c.periods = window.NewCounter(c.window)
+	c.throttled = window.NewCounter(c.window)
+	c.oomKills = window.NewCounter(c.window)
+	return c
+}
@@ -0,0 +1,175 @@
+package cgroup
+
+import (
+	"github.com/christianhujer/assert"
+	"github.com/nelkinda/health-go"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func find(checks []health.Checks, componentID string) health.Checks {
+	for _, check := range checks {
+		if check.ComponentID == componentID {
+			return check
+		}
+	}
+	return health.Checks{}
+}
+
+func tempRoot(t *testing.T, files map[string]string) string {
+	root, err := ioutil.TempDir("", "cgroup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(root) })
+	writeFiles(t, root, files)
+	return root
+}
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestV2(t *testing.T) {
+	checks := Health(WithRoot("testdata/v2")).HealthChecks()
+
+	_ = assert.Equals(t, "v2", checks["cgroup:version"][0].ObservedValue)
+	memory := checks["cgroup:memory"]
+	_ = assert.Equals(t, uint64(456130560), find(memory, "current").ObservedValue)
+	_ = assert.Equals(t, "bytes", find(memory, "current").ObservedUnit)
+	_ = assert.Equals(t, uint64(536870912), find(memory, "max").ObservedValue)
+	_ = assert.Equals(t, health.Warn, find(memory, "utilization").Status)
+	_ = assert.Equals(t, "%", find(memory, "utilization").ObservedUnit)
+	pids := checks["cgroup:pids"]
+	_ = assert.Equals(t, uint64(12), find(pids, "current").ObservedValue)
+	_ = assert.Equals(t, 12.0, find(pids, "utilization").ObservedValue)
+	_ = assert.Equals(t, health.Pass, find(pids, "utilization").Status)
+	cpu := checks["cgroup:cpu"]
+	_ = assert.Equals(t, 0.0, find(cpu, "throttled periods").ObservedValue)
+	_ = assert.Equals(t, int64(1500000000), find(cpu, "throttled time").ObservedValue)
+	_ = assert.Equals(t, uint64(1), checks["cgroup:oomKills"][0].ObservedValue)
+	_ = assert.Equals(t, health.Pass, checks["cgroup:oomKills"][0].Status)
+}
+
+func TestV1(t *testing.T) {
+	checks := Health(WithRoot("testdata/v1"), WithThrottlingThresholds(1, 10)).HealthChecks()
+
+	_ = assert.Equals(t, "v1", checks["cgroup:version"][0].ObservedValue)
+	memory := checks["cgroup:memory"]
+	_ = assert.Equals(t, 1, len(memory))
+	_ = assert.Equals(t, uint64(104857600), find(memory, "current").ObservedValue)
+	pids := checks["cgroup:pids"]
+	_ = assert.Equals(t, 1, len(pids))
+	_ = assert.Equals(t, uint64(7), find(pids, "current").ObservedValue)
+	cpu := checks["cgroup:cpu"]
+	_ = assert.Equals(t, health.Pass, find(cpu, "throttled periods").Status)
+	_ = assert.Equals(t, int64(250000000), find(cpu, "throttled time").ObservedValue)
+	_ = assert.Equals(t, uint64(2), checks["cgroup:oomKills"][0].ObservedValue)
+}
+
+func TestWithinWindow(t *testing.T) {
+	root := tempRoot(t, map[string]string{
+		"cgroup.controllers": "cpu memory\n",
+		"cpu.stat":           "nr_periods 100\nnr_throttled 0\nthrottled_usec 0\n",
+		"memory.events":      "oom_kill 3\n",
+	})
+	c := Health(WithRoot(root), WithThrottlingThresholds(10, 50), WithWindow(time.Minute)).(*cgroup)
+	now := time.Date(2020, 3, 8, 16, 37, 37, 0, time.UTC)
+	c.clock = func() time.Time { return now }
+
+	checks := c.HealthChecks()
+	_ = assert.Equals(t, health.Pass, find(checks["cgroup:cpu"], "throttled periods").Status)
+	_ = assert.Equals(t, health.Pass, checks["cgroup:oomKills"][0].Status)
+	_ = assert.Equals(t, 0, len(checks["cgroup:memory"]))
+
+	now = now.Add(10 * time.Second)
+	writeFiles(t, root, map[string]string{
+		"cpu.stat":      "nr_periods 200\nnr_throttled 60\nthrottled_usec 100\n",
+		"memory.events": "oom_kill 4\n",
+	})
+	for i := 0; i < 3; i++ {
+		checks = c.HealthChecks()
+		_ = assert.Equals(t, 60.0, find(checks["cgroup:cpu"], "throttled periods").ObservedValue)
+		_ = assert.Equals(t, health.Fail, find(checks["cgroup:cpu"], "throttled periods").Status)
+		_ = assert.Equals(t, health.Warn, checks["cgroup:oomKills"][0].Status)
+		_ = assert.Equals(t, "1 processes killed within 1m0s", checks["cgroup:oomKills"][0].Output)
+	}
+
+	now = now.Add(time.Minute)
+	writeFiles(t, root, map[string]string{"cpu.stat": "nr_periods 300\nnr_throttled 65\nthrottled_usec 100\n"})
+	checks = c.HealthChecks()
+	_ = assert.Equals(t, 5.0, find(checks["cgroup:cpu"], "throttled periods").ObservedValue)
+	_ = assert.Equals(t, health.Pass, find(checks["cgroup:cpu"], "throttled periods").Status)
+	_ = assert.Equals(t, health.Pass, checks["cgroup:oomKills"][0].Status)
+	_ = assert.Equals(t, "", checks["cgroup:oomKills"][0].Output)
+}
+
+func TestThresholds(t *testing.T) {
+	root := tempRoot(t, map[string]string{
+		"cgroup.controllers": "memory pids\n",
+		"memory.current":     "95\n",
+		"memory.max":         "100\n",
+		"pids.current":       "95\n",
+		"pids.max":           "100\n",
+	})
+
+	checks := Health(WithRoot(root), WithMemoryThresholds(0, 0), WithPIDsThresholds(50, 90)).HealthChecks()
+
+	_ = assert.Equals(t, health.Pass, find(checks["cgroup:memory"], "utilization").Status)
+	_ = assert.Equals(t, health.Fail, find(checks["cgroup:pids"], "utilization").Status)
+}
+
+func TestInvalidFiles(t *testing.T) {
+	root := tempRoot(t, map[string]string{
+		"cgroup.controllers": "cpu memory pids\n",
+		"memory.current":     "100\n",
+		"memory.max":         "many\n",
+		"pids.current":       "lots\n",
+		"cpu.stat":           "nr_periods -1\n",
+		"memory.events":      "oom_kill x\n",
+	})
+
+	checks := Health(WithRoot(root)).HealthChecks()
+
+	for _, key := range []string{"cgroup:memory", "cgroup:pids", "cgroup:cpu", "cgroup:oomKills"} {
+		_ = assert.Equals(t, 1, len(checks[key]))
+		_ = assert.Equals(t, health.Fail, checks[key][0].Status)
+	}
+	_ = assert.Equals(t, "cgroup: "+filepath.Join(root, "memory.max")+": strconv.ParseUint: parsing \"many\": invalid syntax", checks["cgroup:memory"][0].Output)
+}
+
+func TestUnreadableFiles(t *testing.T) {
+	root := tempRoot(t, map[string]string{
+		"cgroup.controllers": "cpu memory\n",
+		"memory.current/x":   "",
+		"cpu.stat/x":         "",
+	})
+
+	checks := Health(WithRoot(root)).HealthChecks()
+
+	_ = assert.Equals(t, health.Fail, checks["cgroup:memory"][0].Status)
+	_ = assert.Equals(t, health.Fail, checks["cgroup:cpu"][0].Status)
+}
+
+func TestNoCgroup(t *testing.T) {
+	checks := Health(WithRoot("testdata/missing")).HealthChecks()
+
+	_ = assert.Equals(t, 1, len(checks))
+	_ = assert.Equals(t, health.Fail, checks["cgroup:version"][0].Status)
+	_ = assert.Equals(t, "cgroup: no cgroup v1 or v2 found at testdata/missing", checks["cgroup:version"][0].Output)
+}
+
+func TestAuthorizeHealth(t *testing.T) {
+	_ = assert.True(t, Health().AuthorizeHealth(nil))
+}
@@ -0,0 +1,113 @@
+// Package disk provides health checks for the disk space and inode utilization of file systems.
+package disk
+
+import (
+	"fmt"
+	"github.com/nelkinda/health-go"
+	"github.com/nelkinda/health-go/internal/threshold"
+	"net/http"
+	"time"
+)
+
+// usage is the usage of a file system, as reported by statfs.
+type usage struct {
+	// free is the number of bytes free for unprivileged users.
+	free uint64
+	// used is the number of bytes in use.
+	used uint64
+	// files is the number of inodes of the file system.
+	files uint64
+	// filesFree is the number of free inodes.
+	filesFree uint64
+}
+
+// percentage returns part of (part + rest) in percent, like df does.
+func percentage(part uint64, rest uint64) float64 {
+	if part+rest == 0 {
+		return 0
+	}
+	return float64(part) * 100 / float64(part+rest)
+}
+
+type disk struct {
+	mounts        []string
+	warnThreshold float64
+	failThreshold float64
+	usage         func(path string) (usage, error)
+}
+
+// Option configures a disk health check.
+type Option func(*disk)
+
+// WithThresholds sets the utilization in percent above which the check warns or fails.
+// It applies to the utilization of both disk space and inodes.
+// A threshold of 0 disables it.
+// The defaults are 80 and 90.
+func WithThresholds(warn float64, fail float64) Option {
+	return func(d *disk) {
+		d.warnThreshold = warn
+		d.failThreshold = fail
+	}
+}
+
+func (d *disk) HealthChecks() map[string][]health.Checks {
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	result := make(map[string][]health.Checks)
+	for _, mount := range d.mounts {
+		observe := func(key string, value interface{}, unit string, status health.Status, output string) {
+			result[key] = append(result[key], health.Checks{
+				ComponentID:   mount,
+				ComponentType: "system",
+				ObservedValue: value,
+				ObservedUnit:  unit,
+				Status:        status,
+				Output:        output,
+				Time:          now,
+			})
+		}
+		u, err := d.usage(mount)
+		if err != nil {
+			observe("disk:utilization", nil, "", health.Fail, err.Error())
+			continue
+		}
+		utilization := percentage(u.used, u.free)
+		status := threshold.Above(utilization, d.warnThreshold, d.failThreshold)
+		var output string
+		if status != health.Pass {
+			output = fmt.Sprintf("%.1f%% of disk space used", utilization)
+		}
+		observe("disk:utilization", utilization, "%", status, output)
+		observe("disk:used", u.used, "bytes", health.Pass, "")
+		observe("disk:free", u.free, "bytes", health.Pass, "")
+		if u.files > 0 {
+			inodeUtilization := percentage(u.files-u.filesFree, u.filesFree)
+			status := threshold.Above(inodeUtilization, d.warnThreshold, d.failThreshold)
+			var output string
+			if status != health.Pass {
+				output = fmt.Sprintf("%.1f%% of inodes used", inodeUtilization)
+			}
+			observe("disk:inodeUtilization", inodeUtilization, "%", status, output)
+		}
+	}
+	return result
+}
+
+func (*disk) AuthorizeHealth(*http.Request) bool {
+	return true
+}
+
+// Health returns a ChecksProvider for the file systems of the given mount points, like "/" or "/var/lib/postgresql".
+// For each mount point, with the mount point as componentId, it reports
+// the used disk space in percent under "disk:utilization",
+// the used and free bytes under "disk:used" and "disk:free",
+// and the used inodes in percent under "disk:inodeUtilization", unless the file system has no fixed number of inodes.
+// The free bytes are those available to unprivileged users, and the percentages are relative to used plus free, like df reports them.
+// The utilization checks warn or fail above the thresholds, see WithThresholds.
+// On platforms other than Linux, the checks fail.
+func Health(mounts []string, options ...Option) health.ChecksProvider {
+	d := &disk{mounts: mounts, warnThreshold: 80, failThreshold: 90, usage: statfs}
+	for _, option := range options {
+		option(d)
+	}
+	return d
+}
@@ -0,0 +1,32 @@
+// +build linux
+
+package disk
+
+import (
+	"github.com/christianhujer/assert"
+	"github.com/nelkinda/health-go"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStatfs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "disk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	checks := Health([]string{dir, filepath.Join(dir, "missing")}, WithThresholds(0, 0)).HealthChecks()
+
+	utilization := checks["disk:utilization"]
+	_ = assert.Equals(t, 2, len(utilization))
+	_ = assert.Equals(t, dir, utilization[0].ComponentID)
+	_ = assert.Equals(t, health.Pass, utilization[0].Status)
+	value := utilization[0].ObservedValue.(float64)
+	_ = assert.True(t, value >= 0 && value <= 100)
+	_ = assert.True(t, checks["disk:free"][0].ObservedValue.(uint64) > 0)
+	_ = assert.Equals(t, health.Fail, utilization[1].Status)
+	_ = assert.Equals(t, "no such file or directory", utilization[1].Output)
+}
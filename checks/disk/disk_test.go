@@ -0,0 +1,81 @@
+package disk
+
+import (
+	"errors"
+	"github.com/christianhujer/assert"
+	"github.com/nelkinda/health-go"
+	"testing"
+)
+
+func fakeUsage(usages map[string]usage) func(string) (usage, error) {
+	return func(path string) (usage, error) {
+		if u, ok := usages[path]; ok {
+			return u, nil
+		}
+		return usage{}, errors.New("no such file or directory")
+	}
+}
+
+func TestThresholds(t *testing.T) {
+	d := Health([]string{"/", "/data", "/var", "/missing"}).(*disk)
+	d.usage = fakeUsage(map[string]usage{
+		"/":     {used: 50, free: 50, files: 100, filesFree: 10},
+		"/data": {used: 85, free: 15},
+		"/var":  {used: 95, free: 5, files: 100, filesFree: 80},
+	})
+
+	checks := d.HealthChecks()
+
+	utilization := checks["disk:utilization"]
+	_ = assert.Equals(t, 4, len(utilization))
+	_ = assert.Equals(t, "/", utilization[0].ComponentID)
+	_ = assert.Equals(t, 50.0, utilization[0].ObservedValue)
+	_ = assert.Equals(t, "%", utilization[0].ObservedUnit)
+	_ = assert.Equals(t, health.Pass, utilization[0].Status)
+	_ = assert.Equals(t, health.Warn, utilization[1].Status)
+	_ = assert.Equals(t, "85.0% of disk space used", utilization[1].Output)
+	_ = assert.Equals(t, health.Fail, utilization[2].Status)
+	_ = assert.Equals(t, "/missing", utilization[3].ComponentID)
+	_ = assert.Equals(t, health.Fail, utilization[3].Status)
+	_ = assert.Equals(t, "no such file or directory", utilization[3].Output)
+
+	inodes := checks["disk:inodeUtilization"]
+	_ = assert.Equals(t, 2, len(inodes))
+	_ = assert.Equals(t, "/", inodes[0].ComponentID)
+	_ = assert.Equals(t, 90.0, inodes[0].ObservedValue)
+	_ = assert.Equals(t, health.Warn, inodes[0].Status)
+	_ = assert.Equals(t, "90.0% of inodes used", inodes[0].Output)
+	_ = assert.Equals(t, "/var", inodes[1].ComponentID)
+	_ = assert.Equals(t, health.Pass, inodes[1].Status)
+
+	_ = assert.Equals(t, uint64(85), checks["disk:used"][1].ObservedValue)
+	_ = assert.Equals(t, uint64(15), checks["disk:free"][1].ObservedValue)
+	_ = assert.Equals(t, "bytes", checks["disk:free"][1].ObservedUnit)
+}
+
+func TestWithThresholds(t *testing.T) {
+	for _, tc := range []struct {
+		warn     float64
+		fail     float64
+		expected health.Status
+	}{
+		{0, 0, health.Pass},
+		{50, 0, health.Warn},
+		{0, 50, health.Fail},
+		{95, 99, health.Pass},
+	} {
+		d := Health([]string{"/"}, WithThresholds(tc.warn, tc.fail)).(*disk)
+		d.usage = fakeUsage(map[string]usage{"/": {used: 60, free: 40}})
+		_ = assert.Equals(t, tc.expected, d.HealthChecks()["disk:utilization"][0].Status)
+	}
+}
+
+func TestEmptyFileSystem(t *testing.T) {
+	d := Health([]string{"/proc"}).(*disk)
+	d.usage = fakeUsage(map[string]usage{"/proc": {}})
+	_ = assert.Equals(t, 0.0, d.HealthChecks()["disk:utilization"][0].ObservedValue)
+}
+
+func TestAuthorizeHealth(t *testing.T) {
+	_ = assert.True(t, Health(nil).AuthorizeHealth(nil))
+}
@@ -0,0 +1,12 @@
+// +build !linux
+
+package disk
+
+import (
+	"errors"
+	"runtime"
+)
+
+func statfs(string) (usage, error) {
+	return usage{}, errors.New("disk: statfs is not supported on " + runtime.GOOS)
+}
@@ -0,0 +1,20 @@
+// +build linux
+
+package disk
+
+import "syscall"
+
+func statfs(path string) (usage, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return usage{}, err
+	}
+	// Like df, block counts are in units of the fragment size, which can differ from the block size, for example on NFS.
+	fragmentSize := uint64(stat.Frsize)
+	return usage{
+		free:      stat.Bavail * fragmentSize,
+		used:      (stat.Blocks - stat.Bfree) * fragmentSize,
+		files:     stat.Files,
+		filesFree: stat.Ffree,
+	}, nil
+}
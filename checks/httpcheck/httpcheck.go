@@ -0,0 +1,233 @@
+// Package httpcheck provides health checks for arbitrary HTTP endpoints, like REST APIs and webhooks.
+// The response time is reported like in package mongodb, with optional thresholds for Warn and Fail.
+package httpcheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/nelkinda/health-go"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxBodySize limits the size of a response body which is read for assertions.
+const maxBodySize = 1 << 20
+
+type httpCheck struct {
+	name          string
+	url           string
+	method        string
+	header        http.Header
+	statusCodes   map[int]bool
+	bodyRegexp    *regexp.Regexp
+	jsonPath      []string
+	jsonExpected  string
+	client        *http.Client
+	timeout       time.Duration
+	warnThreshold time.Duration
+	failThreshold time.Duration
+}
+
+// Option configures an HTTP health check.
+type Option func(*httpCheck)
+
+// WithMethod sets the HTTP method of the request.
+// The default is GET.
+func WithMethod(method string) Option {
+	return func(c *httpCheck) {
+		c.method = method
+	}
+}
+
+// WithHeader adds a header to the request.
+func WithHeader(name string, value string) Option {
+	return func(c *httpCheck) {
+		c.header.Add(name, value)
+	}
+}
+
+// WithStatusCodes sets the expected HTTP response codes.
+// By default, any code in the 2xx range is expected.
+func WithStatusCodes(statusCodes ...int) Option {
+	return func(c *httpCheck) {
+		c.statusCodes = make(map[int]bool, len(statusCodes))
+		for _, statusCode := range statusCodes {
+			c.statusCodes[statusCode] = true
+		}
+	}
+}
+
+// WithBodyRegexp expects the response body to match bodyRegexp.
+func WithBodyRegexp(bodyRegexp *regexp.Regexp) Option {
+	return func(c *httpCheck) {
+		c.bodyRegexp = bodyRegexp
+	}
+}
+
+// WithJSONPath expects the response body to be JSON with the value expected at the dot-separated path.
+// Array elements are addressed by their index, for example "components.0.status".
+// Values which are not strings are compared in their JSON representation, for example "true" or "42".
+func WithJSONPath(path string, expected string) Option {
+	return func(c *httpCheck) {
+		c.jsonPath = strings.Split(path, ".")
+		c.jsonExpected = expected
+	}
+}
+
+// WithHTTPClient sets the http.Client for the request.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *httpCheck) {
+		c.client = client
+	}
+}
+
+// WithTimeout sets the timeout for the request, including reading the response body.
+// The default is 10 seconds, and 0 means no timeout other than the deadline of the context.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *httpCheck) {
+		c.timeout = timeout
+	}
+}
+
+// WithThresholds sets the response times above which the check warns or fails.
+// A threshold of 0 disables it.
+func WithThresholds(warn time.Duration, fail time.Duration) Option {
+	return func(c *httpCheck) {
+		c.warnThreshold = warn
+		c.failThreshold = fail
+	}
+}
+
+func (c *httpCheck) HealthChecks() map[string][]health.Checks {
+	return c.HealthChecksContext(context.Background())
+}
+
+func (c *httpCheck) HealthChecksContext(ctx context.Context) map[string][]health.Checks {
+	start := time.Now().UTC()
+	checks := health.Checks{
+		ComponentID:   c.url,
+		ComponentType: "component",
+		Time:          start.Format(time.RFC3339Nano),
+	}
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	if err := c.check(ctx); err != nil {
+		checks.Status = health.Fail
+		checks.Output = err.Error()
+		return map[string][]health.Checks{c.name + ":responseTime": {checks}}
+	}
+	responseTime := time.Now().UTC().Sub(start)
+	checks.ObservedValue = responseTime.Nanoseconds()
+	checks.ObservedUnit = "ns"
+	switch {
+	case c.failThreshold > 0 && responseTime > c.failThreshold:
+		checks.Status = health.Fail
+		checks.Output = fmt.Sprintf("response time %v exceeds %v", responseTime, c.failThreshold)
+	case c.warnThreshold > 0 && responseTime > c.warnThreshold:
+		checks.Status = health.Warn
+		checks.Output = fmt.Sprintf("response time %v exceeds %v", responseTime, c.warnThreshold)
+	default:
+		checks.Status = health.Pass
+	}
+	return map[string][]health.Checks{c.name + ":responseTime": {checks}}
+}
+
+// check sends the request and verifies the response.
+func (c *httpCheck) check(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, c.method, c.url, nil)
+	if err != nil {
+		return err
+	}
+	for name, values := range c.header {
+		req.Header[name] = values
+	}
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = res.Body.Close() }()
+	body, err := ioutil.ReadAll(io.LimitReader(res.Body, maxBodySize))
+	if err != nil {
+		return err
+	}
+	if !c.expectedStatusCode(res.StatusCode) {
+		return fmt.Errorf("unexpected HTTP status code %d", res.StatusCode)
+	}
+	if c.bodyRegexp != nil && !c.bodyRegexp.Match(body) {
+		return fmt.Errorf("response body does not match %v", c.bodyRegexp)
+	}
+	if c.jsonPath != nil {
+		return c.checkJSONPath(body)
+	}
+	return nil
+}
+
+func (c *httpCheck) expectedStatusCode(statusCode int) bool {
+	if c.statusCodes == nil {
+		return statusCode >= 200 && statusCode < 300
+	}
+	return c.statusCodes[statusCode]
+}
+
+// checkJSONPath verifies the value at the JSON path of the response body.
+func (c *httpCheck) checkJSONPath(body []byte) error {
+	path := strings.Join(c.jsonPath, ".")
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return fmt.Errorf("response body is not JSON: %v", err)
+	}
+	for _, element := range c.jsonPath {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			value = v[element]
+		case []interface{}:
+			index, err := strconv.Atoi(element)
+			if err != nil || index < 0 || index >= len(v) {
+				return fmt.Errorf("JSON path %s not found", path)
+			}
+			value = v[index]
+		default:
+			return fmt.Errorf("JSON path %s not found", path)
+		}
+	}
+	actual, ok := value.(string)
+	if !ok {
+		encoded, _ := json.Marshal(value)
+		actual = string(encoded)
+	}
+	if actual != c.jsonExpected {
+		return fmt.Errorf("JSON path %s is %s, expected %s", path, actual, c.jsonExpected)
+	}
+	return nil
+}
+
+func (*httpCheck) AuthorizeHealth(*http.Request) bool {
+	return true
+}
+
+// Health returns a ChecksProvider for the HTTP endpoint at url.
+// The check is reported under the key "<name>:responseTime", with the url as componentId.
+// It fails if the request fails, or the response does not meet the expectations configured by the options.
+func Health(name string, url string, options ...Option) health.ChecksProvider {
+	c := &httpCheck{
+		name:    name,
+		url:     url,
+		method:  http.MethodGet,
+		header:  make(http.Header),
+		client:  http.DefaultClient,
+		timeout: 10 * time.Second,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
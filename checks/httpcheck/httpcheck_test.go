@@ -0,0 +1,116 @@
+package httpcheck
+
+import (
+	"github.com/christianhujer/assert"
+	"github.com/nelkinda/health-go"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+)
+
+const statusBody = `{"status": {"indicator": "none"}, "components": [{"name": "API", "status": "operational", "showcase": true}]}`
+
+func newServer(delay time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		switch {
+		case r.URL.Path == "/created" && r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+		case r.URL.Path == "/secret" && r.Header.Get("Authorization") != "Bearer secret":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			_, _ = w.Write([]byte(statusBody))
+		}
+	}))
+}
+
+func check(url string, options ...Option) health.Checks {
+	checks := Health("api", url, options...).HealthChecks()
+	return checks["api:responseTime"][0]
+}
+
+func TestHealthPass(t *testing.T) {
+	server := newServer(0)
+	defer server.Close()
+	for _, tc := range []struct {
+		path    string
+		options []Option
+	}{
+		{"/", nil},
+		{"/created", []Option{WithMethod(http.MethodPost), WithStatusCodes(http.StatusCreated)}},
+		{"/secret", []Option{WithHeader("Authorization", "Bearer secret")}},
+		{"/", []Option{WithBodyRegexp(regexp.MustCompile(`"indicator": "none"`))}},
+		{"/", []Option{WithJSONPath("status.indicator", "none")}},
+		{"/", []Option{WithJSONPath("components.0.showcase", "true")}},
+		{"/", []Option{WithHTTPClient(server.Client()), WithTimeout(time.Second), WithThresholds(time.Second, 2*time.Second)}},
+	} {
+		url := server.URL + tc.path
+		c := check(url, tc.options...)
+		_ = assert.Equals(t, health.Pass, c.Status)
+		_ = assert.Equals(t, url, c.ComponentID)
+		_ = assert.Equals(t, "ns", c.ObservedUnit)
+		_ = assert.Equals(t, "", c.Output)
+	}
+}
+
+func TestHealthFail(t *testing.T) {
+	server := newServer(0)
+	defer server.Close()
+	for _, tc := range []struct {
+		path    string
+		options []Option
+		output  string
+	}{
+		{"/missing", nil, "unexpected HTTP status code 404"},
+		{"/secret", nil, "unexpected HTTP status code 401"},
+		{"/", []Option{WithStatusCodes(http.StatusNoContent)}, "unexpected HTTP status code 200"},
+		{"/", []Option{WithBodyRegexp(regexp.MustCompile(`major`))}, "response body does not match major"},
+		{"/", []Option{WithJSONPath("status.indicator", "major")}, "JSON path status.indicator is none, expected major"},
+		{"/", []Option{WithJSONPath("components.1.status", "operational")}, "JSON path components.1.status not found"},
+		{"/", []Option{WithJSONPath("components.x", "operational")}, "JSON path components.x not found"},
+		{"/", []Option{WithJSONPath("status.indicator.x", "none")}, "JSON path status.indicator.x not found"},
+		{"/", []Option{WithJSONPath("status.missing", "none")}, "JSON path status.missing is null, expected none"},
+		{"/", []Option{WithJSONPath("status", "none")}, `JSON path status is {"indicator":"none"}, expected none`},
+		{"/", []Option{WithMethod("BAD METHOD")}, `net/http: invalid method "BAD METHOD"`},
+	} {
+		c := check(server.URL+tc.path, tc.options...)
+		_ = assert.Equals(t, health.Fail, c.Status)
+		_ = assert.Equals(t, tc.output, c.Output)
+		_ = assert.Nil(t, c.ObservedValue)
+	}
+}
+
+func TestHealthNotJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	c := check(server.URL, WithJSONPath("status", "ok"))
+	_ = assert.Equals(t, health.Fail, c.Status)
+	_ = assert.Equals(t, "response body is not JSON: invalid character 'O' looking for beginning of value", c.Output)
+}
+
+func TestHealthThresholds(t *testing.T) {
+	server := newServer(20 * time.Millisecond)
+	defer server.Close()
+
+	c := check(server.URL, WithThresholds(time.Millisecond, 0))
+	_ = assert.Equals(t, health.Warn, c.Status)
+	_ = assert.True(t, c.ObservedValue.(int64) >= int64(20*time.Millisecond))
+
+	c = check(server.URL, WithThresholds(time.Millisecond, 2*time.Millisecond))
+	_ = assert.Equals(t, health.Fail, c.Status)
+
+	c = check(server.URL, WithTimeout(time.Millisecond))
+	_ = assert.Equals(t, health.Fail, c.Status)
+	_ = assert.True(t, c.Output != "")
+
+	c = check(server.URL, WithTimeout(0))
+	_ = assert.Equals(t, health.Pass, c.Status)
+	_ = assert.Equals(t, "", c.Output)
+	_ = assert.True(t, Health("api", server.URL).AuthorizeHealth(nil))
+}
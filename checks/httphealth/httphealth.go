@@ -0,0 +1,139 @@
+// Package httphealth provides health checks for upstream services which expose a health endpoint in the Health Check Response Format, like services using health-go.
+// The Status of the upstream service becomes a local check, and optionally, the checks of the upstream service are nested under prefixed keys.
+//
+// Services which depend on each other would ask each other for their health endlessly.
+// To prevent this, each request to an upstream health endpoint carries the ids of the services which are already evaluating their health in the ViaHeader.
+// A service which finds its own id in the ViaHeader of the health request does not ask its upstream services again.
+package httphealth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/nelkinda/health-go"
+	"github.com/nelkinda/health-go/client"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ViaHeader is the name of the HTTP header which lists the ids of the services which are evaluating their health, separated by commas.
+const ViaHeader = "Health-Via"
+
+// processID is the default id of this service for cycle detection.
+var processID = newProcessID()
+
+func newProcessID() string {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(id)
+}
+
+type httpHealth struct {
+	name      string
+	url       string
+	client    *client.Client
+	serviceID string
+	nested    bool
+	prefix    string
+}
+
+// Option configures the health checks of an upstream service.
+type Option func(*httpHealth)
+
+// WithClient sets the client which requests the upstream health endpoint, for example to configure a timeout or authorization headers.
+func WithClient(c *client.Client) Option {
+	return func(h *httpHealth) {
+		h.client = c
+	}
+}
+
+// WithServiceID sets the id of this service for cycle detection.
+// The default is an id which is randomly generated for each process.
+func WithServiceID(serviceID string) Option {
+	return func(h *httpHealth) {
+		h.serviceID = serviceID
+	}
+}
+
+// WithNestedChecks re-exports the checks of the upstream service, with prefix prepended to their keys.
+// For example, with prefix "orders/", the upstream check "mongodb:responseTime" becomes "orders/mongodb:responseTime".
+func WithNestedChecks(prefix string) Option {
+	return func(h *httpHealth) {
+		h.nested = true
+		h.prefix = prefix
+	}
+}
+
+func (h *httpHealth) HealthChecks() map[string][]health.Checks {
+	return h.HealthChecksContext(context.Background())
+}
+
+func (h *httpHealth) HealthChecksContext(ctx context.Context) map[string][]health.Checks {
+	via := h.serviceID
+	if r, ok := health.RequestFromContext(ctx); ok {
+		if incoming := r.Header.Get(ViaHeader); incoming != "" {
+			for _, serviceID := range strings.Split(incoming, ",") {
+				if strings.TrimSpace(serviceID) == h.serviceID {
+					// This service is already evaluating its health further up the chain.
+					return map[string][]health.Checks{}
+				}
+			}
+			via = incoming + ", " + h.serviceID
+		}
+	}
+
+	start := time.Now().UTC()
+	checks := health.Checks{
+		ComponentID:   h.url,
+		ComponentType: "component",
+		Time:          start.Format(time.RFC3339Nano),
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.url, nil)
+	if err != nil {
+		checks.Status = health.Fail
+		checks.Output = err.Error()
+		return map[string][]health.Checks{h.name: {checks}}
+	}
+	req.Header.Set(ViaHeader, via)
+	response, err := h.client.Do(req)
+	var statusCodeError *client.StatusCodeError
+	if err != nil && !errors.As(err, &statusCodeError) {
+		checks.Status = health.Fail
+		checks.Output = err.Error()
+		return map[string][]health.Checks{h.name: {checks}}
+	}
+	checks.ObservedValue = response.StatusCode
+	checks.ObservedUnit = "HTTP status code"
+	checks.Status = response.Status
+	checks.Output = response.Output
+	if statusCodeError != nil {
+		checks.Status = health.Fail
+		checks.Output = statusCodeError.Error()
+	}
+	result := map[string][]health.Checks{h.name: {checks}}
+	if h.nested {
+		for key, nestedChecks := range response.Checks {
+			result[h.prefix+key] = append(result[h.prefix+key], nestedChecks...)
+		}
+	}
+	return result
+}
+
+func (*httpHealth) AuthorizeHealth(*http.Request) bool {
+	return true
+}
+
+// Health returns a ChecksProvider for the health of the upstream service with the health endpoint at url.
+// The health of the upstream service is reported under the key name, with the url as componentId and the HTTP response code as observedValue.
+func Health(name string, url string, options ...Option) health.ChecksProvider {
+	h := &httpHealth{name: name, url: url, client: client.New(), serviceID: processID}
+	for _, option := range options {
+		option(h)
+	}
+	return h
+}
@@ -0,0 +1,106 @@
+package httphealth
+
+import (
+	"context"
+	"github.com/christianhujer/assert"
+	"github.com/nelkinda/health-go"
+	"github.com/nelkinda/health-go/client"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type static map[string][]health.Checks
+
+func (s static) HealthChecks() map[string][]health.Checks {
+	return s
+}
+
+func (static) AuthorizeHealth(*http.Request) bool {
+	return true
+}
+
+func serve(statusCode int, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+func TestHealth(t *testing.T) {
+	upstream := health.New(health.Health{}, static{"mongodb:responseTime": {{ComponentID: "mongodb://127.0.0.1:27017", Status: health.Warn}}})
+	server := httptest.NewServer(http.HandlerFunc(upstream.Handler))
+	defer server.Close()
+
+	checks := Health("orders", server.URL).HealthChecks()
+	_ = assert.Equals(t, 1, len(checks))
+	_ = assert.Equals(t, server.URL, checks["orders"][0].ComponentID)
+	_ = assert.Equals(t, "component", checks["orders"][0].ComponentType)
+	_ = assert.Equals(t, health.Warn, checks["orders"][0].Status)
+	_ = assert.Equals(t, http.StatusOK, checks["orders"][0].ObservedValue)
+	_ = assert.True(t, checks["orders"][0].Time != "")
+
+	checks = Health("orders", server.URL, WithNestedChecks("orders/")).HealthChecks()
+	_ = assert.Equals(t, 2, len(checks))
+	_ = assert.Equals(t, "mongodb://127.0.0.1:27017", checks["orders/mongodb:responseTime"][0].ComponentID)
+}
+
+func TestHealthFailures(t *testing.T) {
+	for _, tc := range []struct {
+		statusCode int
+		body       string
+		output     string
+	}{
+		{http.StatusServiceUnavailable, `{"status": "fail", "output": "database down"}`, "database down"},
+		{http.StatusOK, `{"status": "error"}`, "health response: HTTP status code 200 does not match status \"fail\""},
+		{http.StatusServiceUnavailable, `{"status": "pass"}`, "health response: HTTP status code 503 does not match status \"pass\""},
+		{http.StatusOK, `<html></html>`, "health response: invalid JSON: invalid character '<' looking for beginning of value"},
+	} {
+		server := serve(tc.statusCode, tc.body)
+		checks := Health("orders", server.URL).HealthChecks()
+		server.Close()
+		_ = assert.Equals(t, health.Fail, checks["orders"][0].Status)
+		_ = assert.Equals(t, tc.output, checks["orders"][0].Output)
+	}
+
+	checks := Health("orders", "://invalid").HealthChecks()
+	_ = assert.Equals(t, health.Fail, checks["orders"][0].Status)
+
+	server := serve(http.StatusOK, `{"status": "pass"}`)
+	server.Close()
+	checks = Health("orders", server.URL, WithClient(client.New(client.WithHTTPClient(&http.Client{Timeout: time.Second})))).HealthChecks()
+	_ = assert.Equals(t, health.Fail, checks["orders"][0].Status)
+}
+
+func TestCycleDetection(t *testing.T) {
+	var requestsA, requestsB int32
+	var serviceA, serviceB *health.Service
+	serverA := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requestsA, 1)
+		serviceA.Handler(w, r)
+	}))
+	defer serverA.Close()
+	serverB := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requestsB, 1)
+		serviceB.Handler(w, r)
+	}))
+	defer serverB.Close()
+	serviceA = health.New(health.Health{}, Health("b", serverB.URL, WithServiceID("a"), WithNestedChecks("b/")))
+	serviceB = health.New(health.Health{}, Health("a", serverA.URL, WithServiceID("b"), WithNestedChecks("a/")))
+
+	response, err := client.Get(context.Background(), serverA.URL)
+	_ = assert.Nil(t, err)
+	_ = assert.Equals(t, health.Pass, response.Status)
+	_ = assert.Equals(t, 2, int(atomic.LoadInt32(&requestsA)))
+	_ = assert.Equals(t, 1, int(atomic.LoadInt32(&requestsB)))
+	_ = assert.Equals(t, serverB.URL, response.Checks["b"][0].ComponentID)
+	_ = assert.Equals(t, serverA.URL, response.Checks["b/a"][0].ComponentID)
+	_ = assert.Equals(t, 2, len(response.Checks))
+}
+
+func TestProcessID(t *testing.T) {
+	_ = assert.Equals(t, 16, len(newProcessID()))
+	_ = assert.True(t, newProcessID() != newProcessID())
+}
@@ -5,25 +5,82 @@ package mongodb
 import (
 	"context"
 	"github.com/nelkinda/health-go"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 	"net/http"
 	"time"
 )
 
 type mongodb struct {
-	componentID string
-	client      *mongo.Client
-	timeout     time.Duration
-	threshold   time.Duration
+	componentID      string
+	client           *mongo.Client
+	timeout          time.Duration
+	threshold        time.Duration
+	readPreference   *readpref.ReadPref
+	replicaSetStatus bool
+	serverStatus     bool
+}
+
+// Option configures a MongoDB health check.
+type Option func(*mongodb)
+
+// WithReadPreference sets the read preference for pinging and running commands.
+// The default is readpref.Primary().
+func WithReadPreference(readPreference *readpref.ReadPref) Option {
+	return func(m *mongodb) {
+		m.readPreference = readPreference
+	}
+}
+
+// WithReplicaSetStatus runs replSetGetStatus and reports each replica set member under "mongodb:members".
+// A member passes if it is PRIMARY, SECONDARY, or ARBITER, fails if it is unhealthy, and warns otherwise, like while RECOVERING.
+// This requires a replica set; on a standalone server, replSetGetStatus fails, and so does the check.
+func WithReplicaSetStatus() Option {
+	return func(m *mongodb) {
+		m.replicaSetStatus = true
+	}
+}
+
+// WithServerStatus runs serverStatus and reports the current and available connections under "mongodb:connections".
+func WithServerStatus() Option {
+	return func(m *mongodb) {
+		m.serverStatus = true
+	}
+}
+
+// replicaSetStatus is the relevant part of the result of replSetGetStatus.
+type replicaSetStatus struct {
+	Members []member `bson:"members"`
+}
+
+// member is a member of a replica set in the result of replSetGetStatus.
+type member struct {
+	Name                 string  `bson:"name"`
+	Health               float64 `bson:"health"`
+	StateStr             string  `bson:"stateStr"`
+	LastHeartbeatMessage string  `bson:"lastHeartbeatMessage"`
+}
+
+// serverStatus is the relevant part of the result of serverStatus.
+type serverStatus struct {
+	Connections struct {
+		Current   int64 `bson:"current"`
+		Available int64 `bson:"available"`
+	} `bson:"connections"`
 }
 
 func (m *mongodb) HealthChecks() map[string][]health.Checks {
+	return m.HealthChecksContext(context.Background())
+}
+
+func (m *mongodb) HealthChecksContext(ctx context.Context) map[string][]health.Checks {
 	start := time.Now().UTC()
 	startTime := start.Format(time.RFC3339Nano)
-	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
 	defer cancel()
-	err := m.client.Ping(ctx, readpref.Primary())
+	err := m.client.Ping(ctx, m.readPreference)
 	var checks = health.Checks{
 		ComponentID: m.componentID,
 		Time:        startTime,
@@ -42,15 +99,87 @@ func (m *mongodb) HealthChecks() map[string][]health.Checks {
 			checks.Status = health.Pass
 		}
 	}
-	return map[string][]health.Checks{"mongodb:responseTime": {checks}}
+	result := map[string][]health.Checks{"mongodb:responseTime": {checks}}
+	if err != nil {
+		return result
+	}
+	if m.replicaSetStatus {
+		result["mongodb:members"] = m.members(ctx, startTime)
+	}
+	if m.serverStatus {
+		result["mongodb:connections"] = m.connections(ctx, startTime)
+	}
+	return result
+}
+
+// runCommand runs a command on the admin database and decodes its result.
+func (m *mongodb) runCommand(ctx context.Context, command string, result interface{}) error {
+	runCmdOptions := options.RunCmd().SetReadPreference(m.readPreference)
+	return m.client.Database("admin").RunCommand(ctx, bson.D{{Key: command, Value: 1}}, runCmdOptions).Decode(result)
+}
+
+func (m *mongodb) failed(err error, now string) []health.Checks {
+	return []health.Checks{{ComponentID: m.componentID, Status: health.Fail, Output: err.Error(), Time: now}}
+}
+
+func (m *mongodb) members(ctx context.Context, now string) []health.Checks {
+	var status replicaSetStatus
+	if err := m.runCommand(ctx, "replSetGetStatus", &status); err != nil {
+		return m.failed(err, now)
+	}
+	return memberChecks(&status, now)
+}
+
+// memberChecks returns a Checks element for each member of the replica set.
+func memberChecks(status *replicaSetStatus, now string) []health.Checks {
+	members := make([]health.Checks, 0, len(status.Members))
+	for _, member := range status.Members {
+		checks := health.Checks{
+			ComponentID:   member.Name,
+			ObservedValue: member.StateStr,
+			Status:        health.Pass,
+			Output:        member.LastHeartbeatMessage,
+			Time:          now,
+		}
+		switch {
+		case member.Health == 0:
+			checks.Status = health.Fail
+		case member.StateStr != "PRIMARY" && member.StateStr != "SECONDARY" && member.StateStr != "ARBITER":
+			checks.Status = health.Warn
+		}
+		members = append(members, checks)
+	}
+	return members
+}
+
+func (m *mongodb) connections(ctx context.Context, now string) []health.Checks {
+	var status serverStatus
+	if err := m.runCommand(ctx, "serverStatus", &status); err != nil {
+		return m.failed(err, now)
+	}
+	return connectionChecks(&status, now)
+}
+
+// connectionChecks returns the Checks for the current and available connections of the server.
+func connectionChecks(status *serverStatus, now string) []health.Checks {
+	return []health.Checks{
+		{ComponentID: "current", ObservedValue: status.Connections.Current, Status: health.Pass, Time: now},
+		{ComponentID: "available", ObservedValue: status.Connections.Available, Status: health.Pass, Time: now},
+	}
 }
 
 func (*mongodb) AuthorizeHealth(*http.Request) bool {
 	return true
 }
 
-// Health returns a ChecksProvider for health checks about the process uptime.
-// Note that it does not really return the process uptime, but the time since calling this function.
-func Health(componentID string, client *mongo.Client, timeout time.Duration, threshold time.Duration) health.ChecksProvider {
-	return &mongodb{componentID: componentID, client: client, timeout: timeout, threshold: threshold}
+// Health returns a ChecksProvider for health checks about a MongoDB connection.
+// The response time of a ping is reported under "mongodb:responseTime", with the given componentID.
+// It fails if the ping fails or takes longer than timeout, and warns if the ping takes longer than threshold.
+// Further checks are enabled with options.
+func Health(componentID string, client *mongo.Client, timeout time.Duration, threshold time.Duration, opts ...Option) health.ChecksProvider {
+	m := &mongodb{componentID: componentID, client: client, timeout: timeout, threshold: threshold, readPreference: readpref.Primary()}
+	for _, option := range opts {
+		option(m)
+	}
+	return m
 }
@@ -0,0 +1,72 @@
+package mongodb
+
+import (
+	"github.com/christianhujer/assert"
+	"github.com/nelkinda/health-go"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"testing"
+)
+
+const now = "2020-03-08T16:37:37Z"
+
+func TestMemberChecks(t *testing.T) {
+	status := &replicaSetStatus{Members: []member{
+		{Name: "mongo-0:27017", Health: 1, StateStr: "PRIMARY"},
+		{Name: "mongo-1:27017", Health: 1, StateStr: "SECONDARY"},
+		{Name: "mongo-2:27017", Health: 1, StateStr: "ARBITER"},
+		{Name: "mongo-3:27017", Health: 1, StateStr: "RECOVERING"},
+		{Name: "mongo-4:27017", Health: 0, StateStr: "(not reachable/healthy)", LastHeartbeatMessage: "Couldn't get a connection within the time limit"},
+	}}
+
+	_ = assert.DeepEquals(t, []health.Checks{
+		{ComponentID: "mongo-0:27017", ObservedValue: "PRIMARY", Status: health.Pass, Time: now},
+		{ComponentID: "mongo-1:27017", ObservedValue: "SECONDARY", Status: health.Pass, Time: now},
+		{ComponentID: "mongo-2:27017", ObservedValue: "ARBITER", Status: health.Pass, Time: now},
+		{ComponentID: "mongo-3:27017", ObservedValue: "RECOVERING", Status: health.Warn, Time: now},
+		{ComponentID: "mongo-4:27017", ObservedValue: "(not reachable/healthy)", Status: health.Fail, Output: "Couldn't get a connection within the time limit", Time: now},
+	}, memberChecks(status, now))
+	_ = assert.Equals(t, 0, len(memberChecks(&replicaSetStatus{}, now)))
+}
+
+func TestConnectionChecks(t *testing.T) {
+	status := &serverStatus{}
+	status.Connections.Current = 12
+	status.Connections.Available = 838848
+
+	_ = assert.DeepEquals(t, []health.Checks{
+		{ComponentID: "current", ObservedValue: int64(12), Status: health.Pass, Time: now},
+		{ComponentID: "available", ObservedValue: int64(838848), Status: health.Pass, Time: now},
+	}, connectionChecks(status, now))
+}
+
+func TestDecodeCommandResults(t *testing.T) {
+	// The server reports health as double and connections as int32.
+	document, err := bson.Marshal(bson.M{
+		"ok":          1.0,
+		"members":     bson.A{bson.M{"_id": int32(0), "name": "mongo-0:27017", "health": 1.0, "stateStr": "PRIMARY", "lastHeartbeatMessage": ""}},
+		"connections": bson.M{"current": int32(12), "available": int32(838848), "totalCreated": int32(40)},
+	})
+	_ = assert.Nil(t, err)
+
+	var replicaSet replicaSetStatus
+	_ = assert.Nil(t, bson.Unmarshal(document, &replicaSet))
+	_ = assert.DeepEquals(t, []member{{Name: "mongo-0:27017", Health: 1, StateStr: "PRIMARY"}}, replicaSet.Members)
+
+	var server serverStatus
+	_ = assert.Nil(t, bson.Unmarshal(document, &server))
+	_ = assert.Equals(t, int64(12), server.Connections.Current)
+	_ = assert.Equals(t, int64(838848), server.Connections.Available)
+}
+
+func TestOptions(t *testing.T) {
+	m := Health("mongodb://127.0.0.1:27017", nil, 0, 0, WithReplicaSetStatus(), WithServerStatus()).(*mongodb)
+	_ = assert.True(t, m.replicaSetStatus)
+	_ = assert.True(t, m.serverStatus)
+	_ = assert.Equals(t, "primary", m.readPreference.Mode().String())
+	_ = assert.True(t, m.AuthorizeHealth(nil))
+
+	m = Health("mongodb://127.0.0.1:27017", nil, 0, 0, WithReadPreference(readpref.SecondaryPreferred())).(*mongodb)
+	_ = assert.Equals(t, "secondaryPreferred", m.readPreference.Mode().String())
+	_ = assert.False(t, m.replicaSetStatus)
+}
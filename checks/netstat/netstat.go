@@ -0,0 +1,266 @@
+// Package netstat provides health checks for the network stack of Linux, from procfs.
+// Setting a threshold to 0 switches it off.
+package netstat
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/nelkinda/health-go"
+	"github.com/nelkinda/health-go/internal/threshold"
+	"github.com/nelkinda/health-go/internal/window"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultProcRoot is the path where procfs is usually mounted.
+const DefaultProcRoot = "/proc"
+
+type netstat struct {
+	procRoot           string
+	timeWaitWarn       uint64
+	timeWaitFail       uint64
+	conntrackWarn      float64
+	conntrackFail      float64
+	interfaceErrorWarn uint64
+	interfaceErrorFail uint64
+	window             time.Duration
+	clock              func() time.Time
+	// Guards errors.
+	mutex sync.Mutex
+	// The error counters of the interfaces, by name.
+	errors map[string]*window.Counter
+}
+
+// Option configures a netstat health check.
+type Option func(*netstat)
+
+// WithProcRoot sets the path of procfs, DefaultProcRoot by default.
+func WithProcRoot(procRoot string) Option {
+	return func(n *netstat) {
+		n.procRoot = procRoot
+	}
+}
+
+// DefaultWindow is the default time window for new interface errors.
+const DefaultWindow = 5 * time.Minute
+
+// WithWindow sets the time window for new interface errors, DefaultWindow by default.
+// The interface error thresholds apply to the errors within the window,
+// so that every reader sees the same status, regardless of how often the check is read.
+func WithWindow(window time.Duration) Option {
+	return func(n *netstat) {
+		n.window = window
+	}
+}
+
+// WithTimeWaitThresholds sets the number of TCP sockets in TIME_WAIT above which the check warns or fails.
+// Without this option, the number is only reported.
+func WithTimeWaitThresholds(warn uint64, fail uint64) Option {
+	return func(n *netstat) {
+		n.timeWaitWarn = warn
+		n.timeWaitFail = fail
+	}
+}
+
+// WithConntrackThresholds sets the conntrack table utilization in percent above which the check warns or fails.
+// The defaults are 80 and 90.
+func WithConntrackThresholds(warn float64, fail float64) Option {
+	return func(n *netstat) {
+		n.conntrackWarn = warn
+		n.conntrackFail = fail
+	}
+}
+
+// WithInterfaceErrorThresholds sets the number of new errors of an interface within the window above which the check warns or fails.
+// Without this option, the errors are only reported.
+func WithInterfaceErrorThresholds(warn uint64, fail uint64) Option {
+	return func(n *netstat) {
+		n.interfaceErrorWarn = warn
+		n.interfaceErrorFail = fail
+	}
+}
+
+// socketCount is a counter of /proc/net/sockstat, like "TCP tw".
+type socketCount struct {
+	name  string
+	value uint64
+}
+
+// parseSockstat parses /proc/net/sockstat, like "TCP: inuse 27 orphan 0 tw 5 alloc 33 mem 3".
+func parseSockstat(path string) ([]socketCount, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	var counts []socketCount
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || len(fields)%2 != 1 {
+			continue
+		}
+		protocol := strings.TrimSuffix(fields[0], ":")
+		for i := 1; i < len(fields); i += 2 {
+			value, err := strconv.ParseUint(fields[i+1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("netstat: %s: %v", path, err)
+			}
+			counts = append(counts, socketCount{name: protocol + " " + fields[i], value: value})
+		}
+	}
+	return counts, scanner.Err()
+}
+
+// interfaceCounters are the error and drop counters of an interface in /proc/net/dev.
+type interfaceCounters struct {
+	name   string
+	errors uint64
+	drops  uint64
+}
+
+// parseDev parses /proc/net/dev, which after two header lines has a line per interface, like
+// "eth0: 48231589 1504 0 0 0 0 0 0 150000 1064 0 0 0 0 0 0".
+// Of the receive and transmit columns, the third are errors and the fourth are drops.
+func parseDev(path string) ([]interfaceCounters, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	var interfaces []interfaceCounters
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		nameCounters := strings.SplitN(scanner.Text(), ":", 2)
+		if len(nameCounters) != 2 {
+			continue
+		}
+		fields := strings.Fields(nameCounters[1])
+		if len(fields) != 16 {
+			continue
+		}
+		values := make([]uint64, len(fields))
+		for i, field := range fields {
+			if values[i], err = strconv.ParseUint(field, 10, 64); err != nil {
+				return nil, fmt.Errorf("netstat: %s: %v", path, err)
+			}
+		}
+		interfaces = append(interfaces, interfaceCounters{
+			name:   strings.TrimSpace(nameCounters[0]),
+			errors: values[2] + values[10],
+			drops:  values[3] + values[11],
+		})
+	}
+	return interfaces, scanner.Err()
+}
+
+// readValue reads a file with a single value, like nf_conntrack_count.
+func readValue(path string) (uint64, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("netstat: %s: %v", path, err)
+	}
+	return value, nil
+}
+
+func (n *netstat) HealthChecks() map[string][]health.Checks {
+	readAt := n.clock()
+	now := readAt.UTC().Format(time.RFC3339Nano)
+	result := make(map[string][]health.Checks)
+	observe := func(key string, componentID string, value interface{}, unit string, status health.Status) {
+		result[key] = append(result[key], health.Checks{
+			ComponentID:   componentID,
+			ComponentType: "system",
+			ObservedValue: value,
+			ObservedUnit:  unit,
+			Status:        status,
+			Time:          now,
+		})
+	}
+	failed := func(key string, err error) {
+		result[key] = append(result[key], health.Checks{
+			ComponentType: "system",
+			Status:        health.Fail,
+			Output:        err.Error(),
+			Time:          now,
+		})
+	}
+
+	if counts, err := parseSockstat(filepath.Join(n.procRoot, "net", "sockstat")); err != nil {
+		failed("netstat:sockets", err)
+	} else {
+		for _, count := range counts {
+			status := health.Pass
+			if count.name == "TCP tw" {
+				status = threshold.Above(float64(count.value), float64(n.timeWaitWarn), float64(n.timeWaitFail))
+			}
+			observe("netstat:sockets", count.name, count.value, "", status)
+		}
+	}
+
+	conntrack := filepath.Join(n.procRoot, "sys", "net", "netfilter")
+	count, err := readValue(filepath.Join(conntrack, "nf_conntrack_count"))
+	if err == nil {
+		var max uint64
+		if max, err = readValue(filepath.Join(conntrack, "nf_conntrack_max")); err == nil {
+			observe("netstat:conntrack", "count", count, "", health.Pass)
+			observe("netstat:conntrack", "max", max, "", health.Pass)
+			if max > 0 {
+				utilization := float64(count) * 100 / float64(max)
+				observe("netstat:conntrack", "utilization", utilization, "%", threshold.Above(utilization, n.conntrackWarn, n.conntrackFail))
+			}
+		}
+	}
+	// Without the conntrack module, there is no conntrack table to report.
+	if err != nil && !os.IsNotExist(err) {
+		failed("netstat:conntrack", err)
+	}
+
+	if interfaces, err := parseDev(filepath.Join(n.procRoot, "net", "dev")); err != nil {
+		failed("netstat:interfaceErrors", err)
+	} else {
+		n.mutex.Lock()
+		defer n.mutex.Unlock()
+		errors := n.errors
+		n.errors = make(map[string]*window.Counter, len(interfaces))
+		for _, counters := range interfaces {
+			counter, ok := errors[counters.name]
+			if !ok {
+				counter = window.NewCounter(n.window)
+			}
+			n.errors[counters.name] = counter
+			newErrors := counter.Observe(readAt, counters.errors)
+			observe("netstat:interfaceErrors", counters.name, counters.errors, "", threshold.Above(float64(newErrors), float64(n.interfaceErrorWarn), float64(n.interfaceErrorFail)))
+			observe("netstat:interfaceDrops", counters.name, counters.drops, "", health.Pass)
+		}
+	}
+	return result
+}
+
+func (*netstat) AuthorizeHealth(*http.Request) bool {
+	return true
+}
+
+// Health returns a ChecksProvider for the network stack of Linux, read from procfs at DefaultProcRoot, or WithProcRoot.
+// It reports
+// the socket counts of /proc/net/sockstat under "netstat:sockets", with componentIds like "TCP inuse" or "TCP tw" for TIME_WAIT,
+// the count, maximum, and utilization in percent of the conntrack table under "netstat:conntrack", if the conntrack module is loaded,
+// and the receive and transmit errors and drops of each interface in /proc/net/dev under "netstat:interfaceErrors" and "netstat:interfaceDrops".
+// The checks for TIME_WAIT, the conntrack utilization, and new interface errors within the window, see WithWindow, warn or fail above their thresholds.
+func Health(options ...Option) health.ChecksProvider {
+	n := &netstat{procRoot: DefaultProcRoot, conntrackWarn: 80, conntrackFail: 90, window: DefaultWindow, clock: time.Now}
+	for _, option := range options {
+		option(n)
+	}
+	return n
+}
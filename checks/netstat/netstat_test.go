@@ -0,0 +1,136 @@
+package netstat
+
+import (
+	"fmt"
+	"github.com/christianhujer/assert"
+	"github.com/nelkinda/health-go"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func find(checks []health.Checks, componentID string) health.Checks {
+	for _, check := range checks {
+		if check.ComponentID == componentID {
+			return check
+		}
+	}
+	return health.Checks{}
+}
+
+func tempRoot(t *testing.T, files map[string]string) string {
+	root, err := ioutil.TempDir("", "proc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(root) })
+	writeFiles(t, root, files)
+	return root
+}
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestHealth(t *testing.T) {
+	checks := Health(WithProcRoot("testdata/proc"), WithTimeWaitThresholds(1000, 10000)).HealthChecks()
+
+	sockets := checks["netstat:sockets"]
+	_ = assert.Equals(t, 12, len(sockets))
+	_ = assert.Equals(t, "sockets used", sockets[0].ComponentID)
+	_ = assert.Equals(t, uint64(290), sockets[0].ObservedValue)
+	_ = assert.Equals(t, uint64(27), find(sockets, "TCP inuse").ObservedValue)
+	_ = assert.Equals(t, uint64(1500), find(sockets, "TCP tw").ObservedValue)
+	_ = assert.Equals(t, health.Warn, find(sockets, "TCP tw").Status)
+	_ = assert.Equals(t, health.Pass, find(sockets, "TCP inuse").Status)
+	_ = assert.Equals(t, uint64(0), find(sockets, "FRAG memory").ObservedValue)
+
+	conntrack := checks["netstat:conntrack"]
+	_ = assert.Equals(t, uint64(62259), find(conntrack, "count").ObservedValue)
+	_ = assert.Equals(t, uint64(65536), find(conntrack, "max").ObservedValue)
+	_ = assert.Equals(t, "%", find(conntrack, "utilization").ObservedUnit)
+	_ = assert.Equals(t, health.Fail, find(conntrack, "utilization").Status)
+
+	_ = assert.Equals(t, uint64(0), find(checks["netstat:interfaceErrors"], "lo").ObservedValue)
+	_ = assert.Equals(t, uint64(5), find(checks["netstat:interfaceErrors"], "eth0").ObservedValue)
+	_ = assert.Equals(t, health.Pass, find(checks["netstat:interfaceErrors"], "eth0").Status)
+	_ = assert.Equals(t, uint64(8), find(checks["netstat:interfaceDrops"], "eth0").ObservedValue)
+}
+
+func TestInterfaceErrorsWithinWindow(t *testing.T) {
+	dev := "Inter-|   Receive\n face |bytes\n  eth0: 0 0 %s 0 0 0 0 0 0 0 0 0 0 0 0 0\n"
+	root := tempRoot(t, map[string]string{"net/sockstat": "", "net/dev": fmt.Sprintf(dev, "10")})
+	n := Health(WithProcRoot(root), WithInterfaceErrorThresholds(1, 5), WithWindow(time.Minute)).(*netstat)
+	now := time.Date(2020, 3, 8, 16, 37, 37, 0, time.UTC)
+	n.clock = func() time.Time { return now }
+
+	_ = assert.Equals(t, health.Pass, n.HealthChecks()["netstat:interfaceErrors"][0].Status)
+
+	now = now.Add(10 * time.Second)
+	writeFiles(t, root, map[string]string{"net/dev": fmt.Sprintf(dev, "13")})
+	_ = assert.Equals(t, health.Warn, n.HealthChecks()["netstat:interfaceErrors"][0].Status)
+	_ = assert.Equals(t, health.Warn, n.HealthChecks()["netstat:interfaceErrors"][0].Status)
+
+	now = now.Add(10 * time.Second)
+	writeFiles(t, root, map[string]string{"net/dev": fmt.Sprintf(dev, "23")})
+	_ = assert.Equals(t, health.Fail, n.HealthChecks()["netstat:interfaceErrors"][0].Status)
+	_ = assert.Equals(t, health.Fail, n.HealthChecks()["netstat:interfaceErrors"][0].Status)
+
+	now = now.Add(time.Minute)
+	checks := n.HealthChecks()
+	_ = assert.Equals(t, health.Pass, checks["netstat:interfaceErrors"][0].Status)
+	_ = assert.Equals(t, uint64(23), checks["netstat:interfaceErrors"][0].ObservedValue)
+	_ = assert.Equals(t, 0, len(checks["netstat:conntrack"]))
+}
+
+func TestInvalidFiles(t *testing.T) {
+	root := tempRoot(t, map[string]string{
+		"net/sockstat":                         "TCP: inuse many\n",
+		"net/dev":                              "  eth0: 0 0 x 0 0 0 0 0 0 0 0 0 0 0 0 0\n",
+		"sys/net/netfilter/nf_conntrack_count": "1\n",
+		"sys/net/netfilter/nf_conntrack_max":   "unlimited\n",
+	})
+
+	checks := Health(WithProcRoot(root)).HealthChecks()
+
+	for _, key := range []string{"netstat:sockets", "netstat:conntrack", "netstat:interfaceErrors"} {
+		_ = assert.Equals(t, 1, len(checks[key]))
+		_ = assert.Equals(t, health.Fail, checks[key][0].Status)
+	}
+	_ = assert.Equals(t, "netstat: "+filepath.Join(root, "net/sockstat")+": strconv.ParseUint: parsing \"many\": invalid syntax", checks["netstat:sockets"][0].Output)
+}
+
+func TestMissingFiles(t *testing.T) {
+	root := tempRoot(t, map[string]string{"sys/net/netfilter/nf_conntrack_count/x": ""})
+
+	checks := Health(WithProcRoot(root)).HealthChecks()
+
+	_ = assert.Equals(t, health.Fail, checks["netstat:sockets"][0].Status)
+	_ = assert.Equals(t, health.Fail, checks["netstat:interfaceErrors"][0].Status)
+	_ = assert.Equals(t, health.Fail, checks["netstat:conntrack"][0].Status)
+}
+
+func TestEmptyConntrackTable(t *testing.T) {
+	root := tempRoot(t, map[string]string{
+		"sys/net/netfilter/nf_conntrack_count": "0\n",
+		"sys/net/netfilter/nf_conntrack_max":   "0\n",
+	})
+
+	checks := Health(WithProcRoot(root)).HealthChecks()
+
+	_ = assert.Equals(t, 2, len(checks["netstat:conntrack"]))
+}
+
+func TestAuthorizeHealth(t *testing.T) {
+	_ = assert.True(t, Health().AuthorizeHealth(nil))
+}
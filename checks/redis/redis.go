@@ -0,0 +1,229 @@
+// Package redis provides health checks for a Redis server.
+// It speaks the Redis serialization protocol (RESP) itself, so it does not depend on a Redis client library.
+package redis
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"github.com/nelkinda/health-go"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type redis struct {
+	componentID  string
+	address      string
+	timeout      time.Duration
+	threshold    time.Duration
+	username     string
+	password     string
+	tlsConfig    *tls.Config
+	lagThreshold time.Duration
+}
+
+// Option configures a Redis health check.
+type Option func(*redis)
+
+// WithPassword authenticates with the given password, using AUTH.
+// With Redis 6 ACLs, username can be set; otherwise, it must be empty.
+func WithPassword(username string, password string) Option {
+	return func(r *redis) {
+		r.username = username
+		r.password = password
+	}
+}
+
+// WithTLS connects to the Redis server using TLS with the given configuration.
+// If tlsConfig has no ServerName, the host of the address is used.
+func WithTLS(tlsConfig *tls.Config) Option {
+	return func(r *redis) {
+		r.tlsConfig = tlsConfig
+	}
+}
+
+// WithReplicationLagThreshold sets the replication lag above which a replication check warns.
+// By default, the lag is reported without threshold.
+func WithReplicationLagThreshold(threshold time.Duration) Option {
+	return func(r *redis) {
+		r.lagThreshold = threshold
+	}
+}
+
+func (r *redis) HealthChecks() map[string][]health.Checks {
+	return r.HealthChecksContext(context.Background())
+}
+
+func (r *redis) HealthChecksContext(ctx context.Context) map[string][]health.Checks {
+	start := time.Now().UTC()
+	startTime := start.Format(time.RFC3339Nano)
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	failed := func(err error) map[string][]health.Checks {
+		return map[string][]health.Checks{"redis:responseTime": {{
+			ComponentID:   r.componentID,
+			ComponentType: "datastore",
+			Status:        health.Fail,
+			Output:        err.Error(),
+			Time:          startTime,
+		}}}
+	}
+
+	c, closer, err := r.connect(ctx)
+	if err != nil {
+		return failed(err)
+	}
+	defer func() { _ = closer.Close() }()
+
+	pingStart := time.Now()
+	pong, err := c.do("PING")
+	if err != nil {
+		return failed(err)
+	}
+	if pong != "PONG" {
+		return failed(fmt.Errorf("redis: unexpected reply to PING: %v", pong))
+	}
+	responseTime := time.Since(pingStart)
+	responseTimeChecks := health.Checks{
+		ComponentID:   r.componentID,
+		ComponentType: "datastore",
+		ObservedValue: responseTime.Nanoseconds(),
+		ObservedUnit:  "ns",
+		Status:        health.Pass,
+		Time:          startTime,
+	}
+	if responseTime > r.threshold {
+		responseTimeChecks.Status = health.Warn
+	}
+	result := map[string][]health.Checks{"redis:responseTime": {responseTimeChecks}}
+
+	reply, err := c.do("INFO")
+	info, ok := reply.(string)
+	if err != nil || !ok {
+		if err == nil {
+			err = fmt.Errorf("redis: unexpected reply to INFO: %v", reply)
+		}
+		result["redis:info"] = []health.Checks{{ComponentID: r.componentID, ComponentType: "datastore", Status: health.Fail, Output: err.Error(), Time: startTime}}
+		return result
+	}
+	r.addInfoChecks(result, parseInfo(info), startTime)
+	return result
+}
+
+// connect dials the Redis server and authenticates, if configured.
+func (r *redis) connect(ctx context.Context) (*conn, net.Conn, error) {
+	var dialer net.Dialer
+	netConn, err := dialer.DialContext(ctx, "tcp", r.address)
+	if err != nil {
+		return nil, nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = netConn.SetDeadline(deadline)
+	}
+	if r.tlsConfig != nil {
+		tlsConfig := r.tlsConfig.Clone()
+		if tlsConfig.ServerName == "" {
+			if host, _, err := net.SplitHostPort(r.address); err == nil {
+				tlsConfig.ServerName = host
+			}
+		}
+		netConn = tls.Client(netConn, tlsConfig)
+	}
+	c := newConn(netConn)
+	if r.password != "" {
+		args := []string{"AUTH", r.password}
+		if r.username != "" {
+			args = []string{"AUTH", r.username, r.password}
+		}
+		if _, err := c.do(args...); err != nil {
+			_ = netConn.Close()
+			return nil, nil, err
+		}
+	}
+	return c, netConn, nil
+}
+
+// addInfoChecks adds checks for selected fields of the INFO reply.
+func (r *redis) addInfoChecks(result map[string][]health.Checks, info map[string]string, now string) {
+	observe := func(key string, componentID string, componentType string, value interface{}, unit string) {
+		result[key] = append(result[key], health.Checks{
+			ComponentID:   componentID,
+			ComponentType: componentType,
+			ObservedValue: value,
+			ObservedUnit:  unit,
+			Status:        health.Pass,
+			Time:          now,
+		})
+	}
+	if clients, err := strconv.ParseInt(info["connected_clients"], 10, 64); err == nil {
+		observe("redis:connections", r.componentID, "datastore", clients, "")
+	}
+	if usedMemory, err := strconv.ParseUint(info["used_memory"], 10, 64); err == nil {
+		observe("redis:utilization", r.componentID, "datastore", usedMemory, "bytes")
+	}
+	role, ok := info["role"]
+	if !ok {
+		return
+	}
+	observe("redis:role", r.componentID, "datastore", role, "")
+	switch role {
+	case "master":
+		var replicas []string
+		for name := range info {
+			if strings.HasPrefix(name, "slave") && strings.Contains(info[name], "ip=") {
+				replicas = append(replicas, name)
+			}
+		}
+		sort.Strings(replicas)
+		for _, name := range replicas {
+			attributes := parseAttributes(info[name])
+			check := r.lagCheck(net.JoinHostPort(attributes["ip"], attributes["port"]), attributes["lag"], now)
+			if state := attributes["state"]; state != "online" {
+				check.Status = health.Warn
+				check.Output = "replica state " + state
+			}
+			result["redis:replication"] = append(result["redis:replication"], check)
+		}
+	case "slave":
+		check := r.lagCheck(net.JoinHostPort(info["master_host"], info["master_port"]), info["master_last_io_seconds_ago"], now)
+		if link := info["master_link_status"]; link != "up" {
+			check.Status = health.Fail
+			check.Output = "master link status " + link
+		}
+		result["redis:replication"] = append(result["redis:replication"], check)
+	}
+}
+
+// lagCheck reports the replication lag between this server and another server.
+func (r *redis) lagCheck(componentID string, lag string, now string) health.Checks {
+	check := health.Checks{ComponentID: componentID, ComponentType: "datastore", Status: health.Pass, Time: now}
+	if seconds, err := strconv.ParseInt(lag, 10, 64); err == nil && seconds >= 0 {
+		check.ObservedValue = seconds
+		check.ObservedUnit = "s"
+		if r.lagThreshold > 0 && time.Duration(seconds)*time.Second > r.lagThreshold {
+			check.Status = health.Warn
+			check.Output = fmt.Sprintf("replication lag %ds exceeds %v", seconds, r.lagThreshold)
+		}
+	}
+	return check
+}
+
+func (*redis) AuthorizeHealth(*http.Request) bool {
+	return true
+}
+
+// Health returns a ChecksProvider for health checks about the Redis server at address, like "localhost:6379".
+// The response time of PING is reported under "redis:responseTime", with the given componentID.
+// It fails if connecting or PING fails or takes longer than timeout, and warns if PING takes longer than threshold.
+// From INFO, connected_clients is reported under "redis:connections", used_memory under "redis:utilization", role under "redis:role", and the replication lag under "redis:replication".
+func Health(componentID string, address string, timeout time.Duration, threshold time.Duration, options ...Option) health.ChecksProvider {
+	r := &redis{componentID: componentID, address: address, timeout: timeout, threshold: threshold}
+	for _, option := range options {
+		option(r)
+	}
+	return r
+}
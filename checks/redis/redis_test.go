@@ -0,0 +1,313 @@
+package redis
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/christianhujer/assert"
+	"github.com/nelkinda/health-go"
+	"io"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeServer is a minimal Redis server answering AUTH, PING, and INFO.
+type fakeServer struct {
+	listener net.Listener
+	password string
+	delay    time.Duration
+	pong     string
+	info     string
+	tls      *tls.Config
+}
+
+func startFakeServer(t *testing.T, server *fakeServer) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.tls != nil {
+		listener = tls.NewListener(listener, server.tls)
+	}
+	server.listener = listener
+	if server.pong == "" {
+		server.pong = "+PONG"
+	}
+	go func() {
+		for {
+			netConn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(netConn)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func (s *fakeServer) serve(netConn net.Conn) {
+	defer func() { _ = netConn.Close() }()
+	c := newConn(netConn)
+	authenticated := s.password == ""
+	for {
+		request, err := c.readReply()
+		if err != nil {
+			return
+		}
+		args := request.([]interface{})
+		var reply string
+		switch command := strings.ToUpper(args[0].(string)); {
+		case command == "AUTH":
+			if args[len(args)-1] == s.password {
+				authenticated = true
+				reply = "+OK"
+			} else {
+				reply = "-WRONGPASS invalid username-password pair"
+			}
+		case !authenticated:
+			reply = "-NOAUTH Authentication required."
+		case command == "PING":
+			time.Sleep(s.delay)
+			reply = s.pong
+		case command == "INFO":
+			reply = fmt.Sprintf("$%d\r\n%s", len(s.info), s.info)
+		default:
+			reply = "-ERR unknown command"
+		}
+		_, _ = netConn.Write([]byte(reply + "\r\n"))
+	}
+}
+
+const masterInfo = "# Clients\r\nconnected_clients:3\r\n\r\n# Memory\r\nused_memory:1048576\r\n\r\n" +
+	"# Replication\r\nrole:master\r\nconnected_slaves:2\r\n" +
+	"slave0:ip=10.0.0.2,port=6379,state=online,offset=42,lag=0\r\n" +
+	"slave1:ip=10.0.0.3,port=6379,state=wait_bgsave,offset=0,lag=7\r\n"
+
+const replicaInfo = "# Replication\r\nrole:slave\r\nmaster_host:10.0.0.1\r\nmaster_port:6379\r\n" +
+	"master_link_status:down\r\nmaster_last_io_seconds_ago:12\r\n"
+
+func statuses(checks []health.Checks) []health.Status {
+	var result []health.Status
+	for _, check := range checks {
+		result = append(result, check.Status)
+	}
+	return result
+}
+
+func TestHealthMaster(t *testing.T) {
+	server := &fakeServer{info: masterInfo}
+	address := startFakeServer(t, server)
+	defer func() { _ = server.listener.Close() }()
+
+	checks := Health("cache", address, time.Second, time.Second, WithReplicationLagThreshold(5*time.Second)).HealthChecks()
+
+	_ = assert.Equals(t, health.Pass, checks["redis:responseTime"][0].Status)
+	_ = assert.Equals(t, "cache", checks["redis:responseTime"][0].ComponentID)
+	_ = assert.Equals(t, int64(3), checks["redis:connections"][0].ObservedValue)
+	_ = assert.Equals(t, uint64(1048576), checks["redis:utilization"][0].ObservedValue)
+	_ = assert.Equals(t, "bytes", checks["redis:utilization"][0].ObservedUnit)
+	_ = assert.Equals(t, "master", checks["redis:role"][0].ObservedValue)
+	replication := checks["redis:replication"]
+	_ = assert.Equals(t, 2, len(replication))
+	_ = assert.Equals(t, "10.0.0.2:6379", replication[0].ComponentID)
+	_ = assert.Equals(t, int64(0), replication[0].ObservedValue)
+	_ = assert.Equals(t, "10.0.0.3:6379", replication[1].ComponentID)
+	_ = assert.DeepEquals(t, []health.Status{health.Pass, health.Warn}, statuses(replication))
+	_ = assert.Equals(t, "replica state wait_bgsave", replication[1].Output)
+}
+
+func TestHealthReplicationLagThreshold(t *testing.T) {
+	server := &fakeServer{info: "role:master\r\nslave0:ip=10.0.0.2,port=6379,state=online,offset=42,lag=7\r\n"}
+	address := startFakeServer(t, server)
+	defer func() { _ = server.listener.Close() }()
+
+	checks := Health("cache", address, time.Second, time.Second, WithReplicationLagThreshold(5*time.Second)).HealthChecks()
+
+	_ = assert.Equals(t, health.Warn, checks["redis:replication"][0].Status)
+	_ = assert.Equals(t, "replication lag 7s exceeds 5s", checks["redis:replication"][0].Output)
+}
+
+func TestHealthReplica(t *testing.T) {
+	server := &fakeServer{info: replicaInfo}
+	address := startFakeServer(t, server)
+	defer func() { _ = server.listener.Close() }()
+
+	checks := Health("cache", address, time.Second, time.Second).HealthChecks()
+
+	replication := checks["redis:replication"]
+	_ = assert.Equals(t, 1, len(replication))
+	_ = assert.Equals(t, "10.0.0.1:6379", replication[0].ComponentID)
+	_ = assert.Equals(t, int64(12), replication[0].ObservedValue)
+	_ = assert.Equals(t, health.Fail, replication[0].Status)
+	_ = assert.Equals(t, "master link status down", replication[0].Output)
+}
+
+func TestHealthSlowPing(t *testing.T) {
+	server := &fakeServer{delay: 50 * time.Millisecond}
+	address := startFakeServer(t, server)
+	defer func() { _ = server.listener.Close() }()
+
+	checks := Health("cache", address, time.Second, 10*time.Millisecond).HealthChecks()
+
+	_ = assert.Equals(t, health.Warn, checks["redis:responseTime"][0].Status)
+}
+
+func TestHealthTimeout(t *testing.T) {
+	server := &fakeServer{delay: time.Second}
+	address := startFakeServer(t, server)
+	defer func() { _ = server.listener.Close() }()
+
+	checks := Health("cache", address, 50*time.Millisecond, 10*time.Millisecond).HealthChecks()
+
+	_ = assert.Equals(t, health.Fail, checks["redis:responseTime"][0].Status)
+	_ = assert.Equals(t, 1, len(checks))
+}
+
+func TestHealthContextCanceled(t *testing.T) {
+	server := &fakeServer{delay: time.Second}
+	address := startFakeServer(t, server)
+	defer func() { _ = server.listener.Close() }()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	checks := Health("cache", address, time.Minute, time.Second).(health.ContextChecksProvider).HealthChecksContext(ctx)
+
+	_ = assert.Equals(t, health.Fail, checks["redis:responseTime"][0].Status)
+}
+
+func TestHealthUnexpectedPong(t *testing.T) {
+	server := &fakeServer{pong: "+PANG"}
+	address := startFakeServer(t, server)
+	defer func() { _ = server.listener.Close() }()
+
+	checks := Health("cache", address, time.Second, time.Second).HealthChecks()
+
+	_ = assert.Equals(t, health.Fail, checks["redis:responseTime"][0].Status)
+	_ = assert.Equals(t, "redis: unexpected reply to PING: PANG", checks["redis:responseTime"][0].Output)
+}
+
+func TestHealthConnectionRefused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	_ = listener.Close()
+
+	checks := Health("cache", address, time.Second, time.Second).HealthChecks()
+
+	_ = assert.Equals(t, health.Fail, checks["redis:responseTime"][0].Status)
+}
+
+func TestHealthPassword(t *testing.T) {
+	server := &fakeServer{password: "secret", info: "connected_clients:1\r\n"}
+	address := startFakeServer(t, server)
+	defer func() { _ = server.listener.Close() }()
+
+	for _, tc := range []struct {
+		options  []Option
+		expected health.Status
+		output   string
+	}{
+		{nil, health.Fail, "NOAUTH Authentication required."},
+		{[]Option{WithPassword("", "wrong")}, health.Fail, "WRONGPASS invalid username-password pair"},
+		{[]Option{WithPassword("", "secret")}, health.Pass, ""},
+		{[]Option{WithPassword("default", "secret")}, health.Pass, ""},
+	} {
+		checks := Health("cache", address, time.Second, time.Second, tc.options...).HealthChecks()
+		_ = assert.Equals(t, tc.expected, checks["redis:responseTime"][0].Status)
+		_ = assert.Equals(t, tc.output, checks["redis:responseTime"][0].Output)
+	}
+}
+
+func TestHealthTLS(t *testing.T) {
+	// The test server of httptest has a certificate for 127.0.0.1, which is borrowed for the fake Redis server.
+	certificateServer := httptest.NewTLSServer(nil)
+	certificateServer.Close()
+	server := &fakeServer{tls: certificateServer.TLS}
+	address := startFakeServer(t, server)
+	defer func() { _ = server.listener.Close() }()
+	roots := x509.NewCertPool()
+	roots.AddCert(certificateServer.Certificate())
+
+	tlsConfig := &tls.Config{RootCAs: roots}
+	checks := Health("cache", address, time.Second, time.Second, WithTLS(tlsConfig)).HealthChecks()
+	_ = assert.Equals(t, health.Pass, checks["redis:responseTime"][0].Status)
+	_ = assert.Equals(t, "", tlsConfig.ServerName)
+
+	checks = Health("cache", address, time.Second, time.Second, WithTLS(&tls.Config{RootCAs: roots, ServerName: "redis.invalid"})).HealthChecks()
+	_ = assert.Equals(t, health.Fail, checks["redis:responseTime"][0].Status)
+
+	checks = Health("cache", address, time.Second, time.Second, WithTLS(&tls.Config{})).HealthChecks()
+	_ = assert.Equals(t, health.Fail, checks["redis:responseTime"][0].Status)
+	_ = assert.True(t, strings.Contains(checks["redis:responseTime"][0].Output, "certificate"))
+}
+
+func TestHealthReplyLimits(t *testing.T) {
+	for pong, output := range map[string]string{
+		"+" + strings.Repeat("x", 5000):      "redis: reply line exceeds 4096 bytes",
+		"*100000":                            "redis: array of 100000 elements exceeds 1024 elements",
+		strings.Repeat("*1\r\n", 100) + ":1": "redis: arrays nested deeper than 8 levels",
+		"$2000000":                           "redis: bulk string of 2000000 bytes exceeds 1048576 bytes",
+	} {
+		server := &fakeServer{pong: pong}
+		address := startFakeServer(t, server)
+		checks := Health("cache", address, time.Second, time.Second).HealthChecks()
+		_ = server.listener.Close()
+		_ = assert.Equals(t, health.Fail, checks["redis:responseTime"][0].Status)
+		_ = assert.Equals(t, output, checks["redis:responseTime"][0].Output)
+	}
+}
+
+func TestAuthorizeHealth(t *testing.T) {
+	_ = assert.True(t, Health("cache", "localhost:6379", time.Second, time.Second).AuthorizeHealth(nil))
+}
+
+func TestReadReply(t *testing.T) {
+	for _, tc := range []struct {
+		input    string
+		expected interface{}
+		err      string
+	}{
+		{"+OK\r\n", "OK", ""},
+		{"-ERR wrong\r\n", nil, "ERR wrong"},
+		{":42\r\n", int64(42), ""},
+		{"$5\r\nhello\r\n", "hello", ""},
+		{"$-1\r\n", nil, ""},
+		{"*2\r\n$1\r\na\r\n:1\r\n", []interface{}{"a", int64(1)}, ""},
+		{"*-1\r\n", nil, ""},
+		{"\r\n", nil, "redis: empty reply"},
+		{"?\r\n", nil, "redis: unknown reply type '?'"},
+		{"+OK\n", nil, "redis: malformed reply line"},
+		{"$1048577\r\n", nil, "redis: bulk string of 1048577 bytes exceeds 1048576 bytes"},
+		{"*1025\r\n", nil, "redis: array of 1025 elements exceeds 1024 elements"},
+		{"*2147483647\r\n", nil, "redis: array of 2147483647 elements exceeds 1024 elements"},
+		{strings.Repeat("*1\r\n", 8) + ":1\r\n", []interface{}{[]interface{}{[]interface{}{[]interface{}{[]interface{}{[]interface{}{[]interface{}{[]interface{}{int64(1)}}}}}}}}, ""},
+		{strings.Repeat("*1\r\n", 9) + ":1\r\n", nil, "redis: arrays nested deeper than 8 levels"},
+		{"+" + strings.Repeat("x", 4096) + "\r\n", nil, "redis: reply line exceeds 4096 bytes"},
+		{"*2\r\n:1\r\n", nil, "EOF"},
+		{"$x\r\n", nil, `strconv.Atoi: parsing "x": invalid syntax`},
+		{"*x\r\n", nil, `strconv.Atoi: parsing "x": invalid syntax`},
+	} {
+		c := newConn(struct {
+			io.Reader
+			io.Writer
+		}{strings.NewReader(tc.input), nil})
+		actual, err := c.readReply()
+		_ = assert.DeepEquals(t, tc.expected, actual)
+		if tc.err == "" {
+			_ = assert.Nil(t, err)
+		} else {
+			_ = assert.Equals(t, tc.err, err.Error())
+		}
+	}
+}
+
+func TestParseInfo(t *testing.T) {
+	_ = assert.DeepEquals(t, map[string]string{"role": "master", "used_memory": "1024"}, parseInfo("# Replication\r\nrole:master\r\n\r\n# Memory\r\nused_memory:1024\r\n"))
+	_ = assert.DeepEquals(t, map[string]string{"ip": "10.0.0.2", "lag": "0"}, parseAttributes("ip=10.0.0.2,lag=0,invalid"))
+}
@@ -0,0 +1,164 @@
+package redis
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Limits for replies, so that a misbehaving server cannot make a health check allocate without bounds.
+const (
+	// maxLineSize limits the length of reply lines, like simple strings and errors.
+	maxLineSize = 4096
+	// maxReplySize limits the length of bulk strings.
+	maxReplySize = 1 << 20
+	// maxArrayLength limits the number of elements of arrays.
+	maxArrayLength = 1024
+	// maxDepth limits the nesting of arrays.
+	maxDepth = 8
+)
+
+// conn speaks the Redis serialization protocol (RESP) on a connection, just enough for health checks.
+type conn struct {
+	reader *bufio.Reader
+	writer io.Writer
+}
+
+// newConn returns a conn on the given connection, which reads reply lines of up to maxLineSize bytes.
+func newConn(netConn io.ReadWriter) *conn {
+	return &conn{reader: bufio.NewReaderSize(netConn, maxLineSize), writer: netConn}
+}
+
+// do sends a command and reads its reply.
+func (c *conn) do(args ...string) (interface{}, error) {
+	var command strings.Builder
+	_, _ = fmt.Fprintf(&command, "*%d\r\n", len(args))
+	for _, arg := range args {
+		_, _ = fmt.Fprintf(&command, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(c.writer, command.String()); err != nil {
+		return nil, err
+	}
+	return c.readReply()
+}
+
+// serverError is an error reply of the Redis server.
+type serverError string
+
+func (e serverError) Error() string {
+	return string(e)
+}
+
+func (c *conn) readLine() (string, error) {
+	slice, err := c.reader.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		return "", fmt.Errorf("redis: reply line exceeds %d bytes", c.reader.Size())
+	}
+	if err != nil {
+		return "", err
+	}
+	line := string(slice)
+	if !strings.HasSuffix(line, "\r\n") {
+		return "", errors.New("redis: malformed reply line")
+	}
+	return line[:len(line)-2], nil
+}
+
+// readReply reads a reply.
+// Simple strings and bulk strings become string, integers become int64, arrays become []interface{}, nil replies become nil.
+// Error replies become a serverError.
+func (c *conn) readReply() (interface{}, error) {
+	return c.readNested(0)
+}
+
+// readNested reads a reply which is nested in depth arrays.
+func (c *conn) readNested(depth int) (interface{}, error) {
+	line, err := c.readLine()
+	if err != nil {
+		return nil, err
+	}
+	if line == "" {
+		return nil, errors.New("redis: empty reply")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, serverError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		length, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if length < 0 {
+			return nil, nil
+		}
+		if length > maxReplySize {
+			return nil, fmt.Errorf("redis: bulk string of %d bytes exceeds %d bytes", length, maxReplySize)
+		}
+		buf := make([]byte, length+2)
+		if _, err := io.ReadFull(c.reader, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:length]), nil
+	case '*':
+		length, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if length < 0 {
+			return nil, nil
+		}
+		if length > maxArrayLength {
+			return nil, fmt.Errorf("redis: array of %d elements exceeds %d elements", length, maxArrayLength)
+		}
+		if depth >= maxDepth {
+			return nil, fmt.Errorf("redis: arrays nested deeper than %d levels", maxDepth)
+		}
+		// The length is not trusted for allocation, the elements must actually arrive.
+		var elements []interface{}
+		for i := 0; i < length; i++ {
+			element, err := c.readNested(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, element)
+		}
+		return elements, nil
+	default:
+		return nil, fmt.Errorf("redis: unknown reply type %q", line[0])
+	}
+}
+
+// parseInfo parses the reply of the INFO command into its fields.
+func parseInfo(info string) map[string]string {
+	fields := make(map[string]string)
+	for _, line := range strings.Split(info, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		nameValue := strings.SplitN(line, ":", 2)
+		if len(nameValue) == 2 {
+			fields[nameValue[0]] = nameValue[1]
+		}
+	}
+	return fields
+}
+
+// parseAttributes parses comma-separated attributes of an INFO field, like "ip=10.0.0.2,port=6379,state=online,offset=42,lag=0".
+func parseAttributes(value string) map[string]string {
+	attributes := make(map[string]string)
+	for _, attribute := range strings.Split(value, ",") {
+		nameValue := strings.SplitN(attribute, "=", 2)
+		if len(nameValue) == 2 {
+			attributes[nameValue[0]] = nameValue[1]
+		}
+	}
+	return attributes
+}
@@ -0,0 +1,27 @@
+// +build linux
+
+package runtime
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileDescriptors returns the number of open file descriptors of the process, and its limit.
+func fileDescriptors() (open uint64, limit uint64, ok bool, err error) {
+	dir, err := os.Open("/proc/self/fd")
+	if err != nil {
+		return 0, 0, false, err
+	}
+	defer func() { _ = dir.Close() }()
+	names, err := dir.Readdirnames(-1)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	var rlimit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &rlimit); err != nil {
+		return 0, 0, false, err
+	}
+	// The file descriptor for reading the directory itself is not counted.
+	return uint64(len(names) - 1), rlimit.Cur, true, nil
+}
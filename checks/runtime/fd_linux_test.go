@@ -0,0 +1,27 @@
+// +build linux
+
+package runtime
+
+import (
+	"github.com/christianhujer/assert"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestFileDescriptors(t *testing.T) {
+	open, limit, ok, err := fileDescriptors()
+	_ = assert.Nil(t, err)
+	_ = assert.True(t, ok)
+	_ = assert.True(t, open > 0 && open < limit)
+
+	file, err := ioutil.TempFile("", "fd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(file.Name()) }()
+	defer func() { _ = file.Close() }()
+
+	opened, _, _, _ := fileDescriptors()
+	_ = assert.Equals(t, open+1, opened)
+}
@@ -0,0 +1,163 @@
+// Package runtime provides health checks about the Go runtime of the process.
+// All thresholds are optional: a warn or fail threshold of 0 is not checked, which is the default except for file descriptors.
+package runtime
+
+import (
+	"github.com/nelkinda/health-go"
+	"github.com/nelkinda/health-go/internal/threshold"
+	"math"
+	"net/http"
+	"runtime"
+	"sort"
+	"time"
+)
+
+type runtimeChecks struct {
+	goroutinesWarn      int
+	goroutinesFail      int
+	heapWarn            uint64
+	heapFail            uint64
+	gcPauseWarn         time.Duration
+	gcPauseFail         time.Duration
+	fileDescriptorsWarn float64
+	fileDescriptorsFail float64
+	numGoroutine        func() int
+	readMemStats        func(*runtime.MemStats)
+	fileDescriptors     func() (open uint64, limit uint64, ok bool, err error)
+}
+
+// Option configures a runtime health check.
+type Option func(*runtimeChecks)
+
+// WithGoroutineThresholds sets the number of goroutines above which the check warns or fails.
+func WithGoroutineThresholds(warn int, fail int) Option {
+	return func(r *runtimeChecks) {
+		r.goroutinesWarn = warn
+		r.goroutinesFail = fail
+	}
+}
+
+// WithHeapThresholds sets the heap in use in bytes above which the check warns or fails.
+func WithHeapThresholds(warn uint64, fail uint64) Option {
+	return func(r *runtimeChecks) {
+		r.heapWarn = warn
+		r.heapFail = fail
+	}
+}
+
+// WithGCPauseThresholds sets the 99th percentile of GC pauses above which the check warns or fails.
+func WithGCPauseThresholds(warn time.Duration, fail time.Duration) Option {
+	return func(r *runtimeChecks) {
+		r.gcPauseWarn = warn
+		r.gcPauseFail = fail
+	}
+}
+
+// WithFileDescriptorThresholds sets the open file descriptors in percent of RLIMIT_NOFILE above which the check warns or fails.
+// The defaults are 80 and 90.
+func WithFileDescriptorThresholds(warn float64, fail float64) Option {
+	return func(r *runtimeChecks) {
+		r.fileDescriptorsWarn = warn
+		r.fileDescriptorsFail = fail
+	}
+}
+
+// gcPauses returns the recent GC pauses, in ascending order.
+func gcPauses(memStats *runtime.MemStats) []uint64 {
+	n := int(memStats.NumGC)
+	if n > len(memStats.PauseNs) {
+		n = len(memStats.PauseNs)
+	}
+	pauses := make([]uint64, n)
+	for i := range pauses {
+		pauses[i] = memStats.PauseNs[(int(memStats.NumGC)-1-i+len(memStats.PauseNs))%len(memStats.PauseNs)]
+	}
+	sort.Slice(pauses, func(i, j int) bool { return pauses[i] < pauses[j] })
+	return pauses
+}
+
+// percentile returns the percentile of sorted values, using the nearest-rank method.
+func percentile(sorted []uint64, p float64) uint64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func (r *runtimeChecks) HealthChecks() map[string][]health.Checks {
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	result := make(map[string][]health.Checks)
+	observe := func(key string, componentID string, value interface{}, unit string, status health.Status) {
+		result[key] = append(result[key], health.Checks{
+			ComponentID:   componentID,
+			ComponentType: "process",
+			ObservedValue: value,
+			ObservedUnit:  unit,
+			Status:        status,
+			Time:          now,
+		})
+	}
+
+	goroutines := r.numGoroutine()
+	observe("runtime:goroutines", "", goroutines, "", threshold.Above(float64(goroutines), float64(r.goroutinesWarn), float64(r.goroutinesFail)))
+	observe("runtime:gomaxprocs", "", runtime.GOMAXPROCS(0), "", health.Pass)
+
+	var memStats runtime.MemStats
+	r.readMemStats(&memStats)
+	observe("runtime:heap", "in use", memStats.HeapInuse, "bytes", threshold.Above(float64(memStats.HeapInuse), float64(r.heapWarn), float64(r.heapFail)))
+	if pauses := gcPauses(&memStats); len(pauses) > 0 {
+		for _, p := range []struct {
+			componentID string
+			percentile  float64
+		}{{"p50", 50}, {"p90", 90}, {"p99", 99}} {
+			pause := percentile(pauses, p.percentile)
+			status := health.Pass
+			if p.percentile == 99 {
+				status = threshold.Above(float64(pause), float64(r.gcPauseWarn), float64(r.gcPauseFail))
+			}
+			observe("runtime:gcPause", p.componentID, pause, "ns", status)
+		}
+		observe("runtime:gcPause", "max", pauses[len(pauses)-1], "ns", health.Pass)
+		observe("runtime:lastGC", "", time.Unix(0, int64(memStats.LastGC)).UTC().Format(time.RFC3339Nano), "", health.Pass)
+	}
+
+	if open, limit, ok, err := r.fileDescriptors(); err != nil {
+		result["runtime:fileDescriptors"] = []health.Checks{{ComponentType: "process", Status: health.Fail, Output: err.Error(), Time: now}}
+	} else if ok {
+		observe("runtime:fileDescriptors", "open", open, "", health.Pass)
+		observe("runtime:fileDescriptors", "limit", limit, "", health.Pass)
+		if limit > 0 {
+			utilization := float64(open) * 100 / float64(limit)
+			observe("runtime:fileDescriptors", "utilization", utilization, "%", threshold.Above(utilization, r.fileDescriptorsWarn, r.fileDescriptorsFail))
+		}
+	}
+	return result
+}
+
+func (*runtimeChecks) AuthorizeHealth(*http.Request) bool {
+	return true
+}
+
+// Health returns a ChecksProvider for health checks about the Go runtime.
+// It reports
+// the number of goroutines under "runtime:goroutines",
+// GOMAXPROCS under "runtime:gomaxprocs",
+// the heap in use under "runtime:heap",
+// the 50th, 90th, and 99th percentile and the maximum of the recent GC pauses under "runtime:gcPause",
+// the time of the last GC under "runtime:lastGC",
+// and on Linux, the open file descriptors, their limit RLIMIT_NOFILE, and the utilization under "runtime:fileDescriptors".
+// The checks warn or fail above their thresholds, set by options.
+func Health(options ...Option) health.ChecksProvider {
+	r := &runtimeChecks{
+		fileDescriptorsWarn: 80,
+		fileDescriptorsFail: 90,
+		numGoroutine:        runtime.NumGoroutine,
+		readMemStats:        runtime.ReadMemStats,
+		fileDescriptors:     fileDescriptors,
+	}
+	for _, option := range options {
+		option(r)
+	}
+	return r
+}
@@ -0,0 +1,112 @@
+package runtime
+
+import (
+	"errors"
+	"github.com/christianhujer/assert"
+	"github.com/nelkinda/health-go"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func find(checks []health.Checks, componentID string) health.Checks {
+	for _, check := range checks {
+		if check.ComponentID == componentID {
+			return check
+		}
+	}
+	return health.Checks{}
+}
+
+func fake(r health.ChecksProvider, goroutines int, memStats runtime.MemStats, open uint64, limit uint64, err error) health.ChecksProvider {
+	checks := r.(*runtimeChecks)
+	checks.numGoroutine = func() int { return goroutines }
+	checks.readMemStats = func(m *runtime.MemStats) { *m = memStats }
+	checks.fileDescriptors = func() (uint64, uint64, bool, error) { return open, limit, err == nil, err }
+	return checks
+}
+
+func TestHealth(t *testing.T) {
+	runtime.GC()
+
+	checks := Health().HealthChecks()
+
+	_ = assert.True(t, checks["runtime:goroutines"][0].ObservedValue.(int) > 0)
+	_ = assert.Equals(t, runtime.GOMAXPROCS(0), checks["runtime:gomaxprocs"][0].ObservedValue)
+	_ = assert.True(t, checks["runtime:heap"][0].ObservedValue.(uint64) > 0)
+	_ = assert.Equals(t, 4, len(checks["runtime:gcPause"]))
+	lastGC, err := time.Parse(time.RFC3339Nano, checks["runtime:lastGC"][0].ObservedValue.(string))
+	_ = assert.Nil(t, err)
+	_ = assert.True(t, time.Since(lastGC) < time.Minute)
+	for _, values := range checks {
+		for _, check := range values {
+			_ = assert.Equals(t, health.Pass, check.Status)
+			_ = assert.Equals(t, "process", check.ComponentType)
+		}
+	}
+}
+
+func TestThresholds(t *testing.T) {
+	memStats := runtime.MemStats{HeapInuse: 2048, NumGC: 300, LastGC: uint64(time.Date(2020, 3, 8, 16, 37, 37, 0, time.UTC).UnixNano())}
+	for i := range memStats.PauseNs {
+		memStats.PauseNs[i] = uint64(i + 1)
+	}
+	r := fake(Health(
+		WithGoroutineThresholds(100, 1000),
+		WithHeapThresholds(1024, 4096),
+		WithGCPauseThresholds(200, 250),
+		WithFileDescriptorThresholds(50, 0),
+	), 1001, memStats, 60, 100, nil)
+
+	checks := r.HealthChecks()
+
+	_ = assert.Equals(t, 1001, checks["runtime:goroutines"][0].ObservedValue)
+	_ = assert.Equals(t, health.Fail, checks["runtime:goroutines"][0].Status)
+	_ = assert.Equals(t, health.Warn, checks["runtime:heap"][0].Status)
+	gcPause := checks["runtime:gcPause"]
+	_ = assert.Equals(t, uint64(128), find(gcPause, "p50").ObservedValue)
+	_ = assert.Equals(t, uint64(231), find(gcPause, "p90").ObservedValue)
+	_ = assert.Equals(t, uint64(254), find(gcPause, "p99").ObservedValue)
+	_ = assert.Equals(t, health.Fail, find(gcPause, "p99").Status)
+	_ = assert.Equals(t, uint64(256), find(gcPause, "max").ObservedValue)
+	_ = assert.Equals(t, "2020-03-08T16:37:37Z", checks["runtime:lastGC"][0].ObservedValue)
+	fileDescriptors := checks["runtime:fileDescriptors"]
+	_ = assert.Equals(t, uint64(60), find(fileDescriptors, "open").ObservedValue)
+	_ = assert.Equals(t, uint64(100), find(fileDescriptors, "limit").ObservedValue)
+	_ = assert.Equals(t, 60.0, find(fileDescriptors, "utilization").ObservedValue)
+	_ = assert.Equals(t, health.Warn, find(fileDescriptors, "utilization").Status)
+}
+
+func TestRecentGCPauses(t *testing.T) {
+	memStats := runtime.MemStats{NumGC: 3}
+	memStats.PauseNs[0], memStats.PauseNs[1], memStats.PauseNs[2], memStats.PauseNs[3] = 30, 10, 20, 1000
+
+	checks := fake(Health(), 1, memStats, 0, 0, nil).HealthChecks()
+
+	_ = assert.Equals(t, uint64(20), find(checks["runtime:gcPause"], "p50").ObservedValue)
+	_ = assert.Equals(t, uint64(30), find(checks["runtime:gcPause"], "max").ObservedValue)
+	_ = assert.Equals(t, 2, len(checks["runtime:fileDescriptors"]))
+}
+
+func TestNoGC(t *testing.T) {
+	checks := fake(Health(), 1, runtime.MemStats{}, 0, 0, nil).HealthChecks()
+
+	_ = assert.Equals(t, 0, len(checks["runtime:gcPause"]))
+	_ = assert.Equals(t, 0, len(checks["runtime:lastGC"]))
+}
+
+func TestFileDescriptorsError(t *testing.T) {
+	checks := fake(Health(), 1, runtime.MemStats{}, 0, 0, errors.New("no fds")).HealthChecks()
+
+	_ = assert.Equals(t, health.Fail, checks["runtime:fileDescriptors"][0].Status)
+	_ = assert.Equals(t, "no fds", checks["runtime:fileDescriptors"][0].Output)
+}
+
+func TestPercentile(t *testing.T) {
+	_ = assert.Equals(t, uint64(7), percentile([]uint64{7}, 0))
+	_ = assert.Equals(t, uint64(7), percentile([]uint64{7}, 100))
+}
+
+func TestAuthorizeHealth(t *testing.T) {
+	_ = assert.True(t, Health().AuthorizeHealth(nil))
+}
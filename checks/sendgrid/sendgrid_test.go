@@ -1,9 +1,14 @@
 package sendgrid
 
 import (
-	"fmt"
-	"os"
+	"github.com/christianhujer/assert"
+	"github.com/nelkinda/health-go"
+	"github.com/nelkinda/health-go/checks/statuspage"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
 const sampleResponse = `{
@@ -20,8 +25,95 @@ const sampleResponse = `{
   "incidents": [{"name":"Singapore Data Center Maintenance: Monday, February 18th, 8AM MST - 12PM MST","status":"completed","created_at":"2019-02-15T13:09:52.515-08:00","updated_at":"2019-02-18T11:45:41.081-08:00","monitoring_at":null,"resolved_at":"2019-02-18T11:45:40.600-08:00","impact":"maintenance","shortlink":"http://stspg.io/693e39885","scheduled_for":"2019-02-18T07:00:00.000-08:00","scheduled_until":"2019-02-18T11:00:00.000-08:00","scheduled_remind_prior":true,"scheduled_reminded_at":"2019-02-18T05:59:56.555-08:00","impact_override":null,"scheduled_auto_in_progress":true,"scheduled_auto_completed":false,"metadata":{},"id":"lrykfvn3wcsp","page_id":"3tgl2vf85cht","incident_updates":[{"status":"completed","body":"This scheduled maintenance has been completed.  Thank you again for your patience.","created_at":"2019-02-18T11:45:40.600-08:00","wants_twitter_update":true,"twitter_updated_at":"2019-02-18T11:45:41.070-08:00","updated_at":"2019-02-18T11:45:41.077-08:00","display_at":"2019-02-18T11:45:40.600-08:00","affected_components":[{"code":"s0rtr2pvxgzq","name":"Mail Sending - API v3","old_status":"under_maintenance","new_status":"operational"},{"code":"nqgmzl9pxtd5","name":"Mail Sending - SMTP","old_status":"under_maintenance","new_status":"operational"},{"code":"q6bv40cywctx","name":"Mail Sending - API v2","old_status":"under_maintenance","new_status":"operational"}],"deliver_notifications":true,"tweet_id":1097582945475342336,"id":"gmxv8svpcx5t","incident_id":"lrykfvn3wcsp","custom_tweet":null},{"status":"in_progress","body":"Scheduled maintenance is currently in progress. We will provide updates as necessary.","created_at":"2019-02-18T07:00:04.725-08:00","wants_twitter_update":true,"twitter_updated_at":"2019-02-18T07:00:06.259-08:00","updated_at":"2019-02-18T07:00:06.261-08:00","display_at":"2019-02-18T07:00:04.725-08:00","affected_components":[{"code":"s0rtr2pvxgzq","name":"Mail Sending - API v3","old_status":"operational","new_status":"under_maintenance"},{"code":"nqgmzl9pxtd5","name":"Mail Sending - SMTP","old_status":"operational","new_status":"under_maintenance"},{"code":"q6bv40cywctx","name":"Mail Sending - API v2","old_status":"operational","new_status":"under_maintenance"}],"deliver_notifications":true,"tweet_id":1097511076747300865,"id":"z3yd44zxbs59","incident_id":"lrykfvn3wcsp","custom_tweet":null},{"status":"scheduled","body":"Maintenance will begin as scheduled in 60 minutes.","created_at":"2019-02-18T05:59:55.986-08:00","wants_twitter_update":true,"twitter_updated_at":"2019-02-18T05:59:56.745-08:00","updated_at":"2019-02-18T05:59:56.748-08:00","display_at":"2019-02-18T05:59:55.986-08:00","affected_components":null,"deliver_notifications":true,"tweet_id":1097495937398657025,"id":"0s5nb2qbvj2d","incident_id":"lrykfvn3wcsp","custom_tweet":null},{"status":"scheduled","body":"One of our third party network providers will be performing a maintenance on Monday February 18th, from 8AM - 12PM MST.  Latency may be experienced by customers in the Asia Pacific region during this window.  We anticipate no other impact, and will follow up once this maintenance is complete.  We apologize for any inconvenience this may cause.  Please contact Support with any questions: https://support.sendgrid.com/","created_at":"2019-02-15T13:09:52.561-08:00","wants_twitter_update":true,"twitter_updated_at":"2019-02-15T13:09:53.076-08:00","updated_at":"2019-02-15T13:09:53.082-08:00","display_at":"2019-02-15T13:09:52.561-08:00","affected_components":[{"code":"s0rtr2pvxgzq","name":"Mail Sending - API v3","old_status":"operational","new_status":"operational"},{"code":"nqgmzl9pxtd5","name":"Mail Sending - SMTP","old_status":"operational","new_status":"operational"},{"code":"q6bv40cywctx","name":"Mail Sending - API v2","old_status":"operational","new_status":"operational"}],"deliver_notifications":true,"tweet_id":1096516971556790272,"id":"3129405n4xwj","incident_id":"lrykfvn3wcsp","custom_tweet":null}],"postmortem_body":null,"postmortem_body_last_updated_at":null,"postmortem_ignored":false,"postmortem_published_at":null,"postmortem_notified_subscribers":false,"postmortem_notified_twitter":false,"components":[{"status":"operational","name":"API v3","created_at":"2016-05-10T12:44:00.908-07:00","updated_at":"2019-02-18T11:45:40.533-08:00","position":1,"description":"The flow of mail generated by mail.send API requests","showcase":false,"id":"s0rtr2pvxgzq","page_id":"3tgl2vf85cht","group_id":"06b486vkpf0h"},{"status":"operational","name":"SMTP","created_at":"2015-11-18T06:18:39.550-08:00","updated_at":"2019-02-18T11:45:40.551-08:00","position":2,"description":"Mail sent with direct integrations to smtp.sendgrid.net.","showcase":false,"id":"nqgmzl9pxtd5","page_id":"3tgl2vf85cht","group_id":"06b486vkpf0h"},{"status":"operational","name":"API v2","created_at":"2015-11-18T06:18:04.353-08:00","updated_at":"2019-02-18T11:45:40.569-08:00","position":3,"description":"The flow of mail generated by mail.send API requests.","showcase":false,"id":"q6bv40cywctx","page_id":"3tgl2vf85cht","group_id":"06b486vkpf0h"}]},{"name":"Service Disruption: New Account Signup","status":"resolved","created_at":"2019-02-15T08:40:50.941-08:00","updated_at":"2019-02-15T12:46:51.569-08:00","monitoring_at":"2019-02-15T12:23:52.305-08:00","resolved_at":"2019-02-15T12:46:50.840-08:00","impact":"major","shortlink":"http://stspg.io/fd96e8354","scheduled_for":null,"scheduled_until":null,"scheduled_remind_prior":false,"scheduled_reminded_at":null,"impact_override":null,"scheduled_auto_in_progress":false,"scheduled_auto_completed":false,"metadata":{},"id":"81t86166773w","page_id":"3tgl2vf85cht","incident_updates":[{"status":"resolved","body":"Issues with new account signup and sub-user account creation have been resolved. If you were unable to create a new account or a sub-user account during this timeframe, please retry creation with a different username from the previous attempt. We greatly appreciate your patience through this process and apologize for any inconvenience this may have caused. Please contact our Support team with any questions at https://support.sendgrid.com/.","created_at":"2019-02-15T12:46:50.840-08:00","wants_twitter_update":true,"twitter_updated_at":"2019-02-15T12:46:51.560-08:00","updated_at":"2019-02-15T12:46:51.566-08:00","display_at":"2019-02-15T12:46:50.840-08:00","affected_components":[{"code":"4kpfnh9zn3cq","name":"Website","old_status":"partial_outage","new_status":"operational"},{"code":"vgl8t6wflyjt","name":"Other Services - API","old_status":"partial_outage","new_status":"operational"}],"deliver_notifications":true,"tweet_id":1096511176966459392,"id":"l8phbt22knjm","incident_id":"81t86166773w","custom_tweet":null},{"status":"monitoring","body":"Our Operations teams have identified the source of the issue and implemented a fix for new account signup and sub-user account creation. If you were unable to create a new account or a sub-user account during this timeframe, please create a new account or new sub-user with a different username from the prior attempt. We are continuing to monitor the situation and will provide an update when we confirm the issue has been resolved. We apologize for any inconvenience this may have caused and greatly appreciate your patience. Please reach out to our Support team with any questions at https://support.sendgrid.com/","created_at":"2019-02-15T12:23:52.305-08:00","wants_twitter_update":true,"twitter_updated_at":"2019-02-15T12:23:52.673-08:00","updated_at":"2019-02-15T12:23:52.676-08:00","display_at":"2019-02-15T12:23:52.305-08:00","affected_components":[{"code":"4kpfnh9zn3cq","name":"Website","old_status":"partial_outage","new_status":"partial_outage"},{"code":"vgl8t6wflyjt","name":"Other Services - API","old_status":"partial_outage","new_status":"partial_outage"}],"deliver_notifications":true,"tweet_id":1096505393449099264,"id":"ss0vkfv0q7wn","incident_id":"81t86166773w","custom_tweet":null},{"status":"investigating","body":"Our Operations teams are continuing to investigate intermittent issues with paid and free account signup and sub-user account creation and have no new information to provide at this time. As new information becomes available, we will provide further updates. We greatly appreciate your patience and apologize for any inconvenience this may be causing. Please contact our Support team with any questions: https://support.sendgrid.com/.","created_at":"2019-02-15T11:42:42.153-08:00","wants_twitter_update":true,"twitter_updated_at":"2019-02-15T11:42:42.604-08:00","updated_at":"2019-02-15T11:42:42.611-08:00","display_at":"2019-02-15T11:42:42.153-08:00","affected_components":[{"code":"4kpfnh9zn3cq","name":"Website","old_status":"partial_outage","new_status":"partial_outage"},{"code":"vgl8t6wflyjt","name":"Other Services - API","old_status":"partial_outage","new_status":"partial_outage"}],"deliver_notifications":true,"tweet_id":1096495033203666944,"id":"401xpjg3lwt0","incident_id":"81t86166773w","custom_tweet":null},{"status":"investigating","body":"Our Operations teams are continuing to investigate intermittent issues with paid and free account signup and sub-user account creation. As further information becomes available, we will provide updates. We greatly appreciate your patience and apologize for any inconvenience this may be causing. Please contact our Support team with any questions: https://support.sendgrid.com/.","created_at":"2019-02-15T10:33:16.190-08:00","wants_twitter_update":true,"twitter_updated_at":"2019-02-15T10:33:16.695-08:00","updated_at":"2019-02-15T10:33:16.698-08:00","display_at":"2019-02-15T10:33:16.190-08:00","affected_components":[{"code":"4kpfnh9zn3cq","name":"Website","old_status":"partial_outage","new_status":"partial_outage"},{"code":"vgl8t6wflyjt","name":"Other Services - API","old_status":"partial_outage","new_status":"partial_outage"}],"deliver_notifications":true,"tweet_id":1096477559796174848,"id":"qrxw9c5tcwld","incident_id":"81t86166773w","custom_tweet":null},{"status":"investigating","body":"Our Operations teams continue investigating issues with free and paid account signup. Additionally, sub-user account creation appears to be impacted by this issue. As more information becomes available, we will continue to provide updates. We apologize for any inconvenience this may be causing and appreciate your patience. Please contact our Support team with any questions: https://support.sendgrid.com/.","created_at":"2019-02-15T09:55:36.706-08:00","wants_twitter_update":true,"twitter_updated_at":"2019-02-15T09:55:37.415-08:00","updated_at":"2019-02-15T09:55:37.425-08:00","display_at":"2019-02-15T09:55:36.706-08:00","affected_components":[{"code":"4kpfnh9zn3cq","name":"Website","old_status":"partial_outage","new_status":"partial_outage"},{"code":"vgl8t6wflyjt","name":"Other Services - API","old_status":"operational","new_status":"partial_outage"}],"deliver_notifications":true,"tweet_id":1096468084062212096,"id":"wrnpm3s2yjfw","incident_id":"81t86166773w","custom_tweet":null},{"status":"investigating","body":"Our Operations teams continue to investigate issues with free and paid account signup at this time. As more information becomes available, we will provide an update. We apologize for any inconvenience this may be causing and appreciate your patience. Please contact our Support team with any questions: https://support.sendgrid.com/.","created_at":"2019-02-15T09:25:39.220-08:00","wants_twitter_update":true,"twitter_updated_at":"2019-02-15T09:25:40.006-08:00","updated_at":"2019-02-15T09:25:40.015-08:00","display_at":"2019-02-15T09:25:39.220-08:00","affected_components":[{"code":"4kpfnh9zn3cq","name":"Website","old_status":"partial_outage","new_status":"partial_outage"}],"deliver_notifications":true,"tweet_id":1096460545014648832,"id":"yzlwd14s1ggz","incident_id":"81t86166773w","custom_tweet":null},{"status":"investigating","body":"We are currently investigating issues with free and paid new account signups. Once we have more information we will provide it. We appreciate your patience and apologize for any inconvenience this may be causing.","created_at":"2019-02-15T08:40:50.997-08:00","wants_twitter_update":true,"twitter_updated_at":"2019-02-15T08:40:51.662-08:00","updated_at":"2019-02-15T08:40:51.666-08:00","display_at":"2019-02-15T08:40:50.997-08:00","affected_components":[{"code":"4kpfnh9zn3cq","name":"Website","old_status":"operational","new_status":"partial_outage"}],"deliver_notifications":true,"tweet_id":1096449269496569856,"id":"44jdq7dt4q5l","incident_id":"81t86166773w","custom_tweet":null}],"postmortem_body":null,"postmortem_body_last_updated_at":null,"postmortem_ignored":false,"postmortem_published_at":null,"postmortem_notified_subscribers":false,"postmortem_notified_twitter":false,"components":[{"status":"operational","name":"API","created_at":"2015-11-18T06:25:16.300-08:00","updated_at":"2019-02-15T12:46:50.796-08:00","position":1,"description":"General API functionality not including mail.send calls.","showcase":false,"id":"vgl8t6wflyjt","page_id":"3tgl2vf85cht","group_id":"zpsd56vwkzvq"},{"status":"operational","name":"Website","created_at":"2015-11-18T06:19:03.286-08:00","updated_at":"2019-02-15T12:46:50.810-08:00","position":5,"description":"Availability of SendGrid.com","showcase":false,"id":"4kpfnh9zn3cq","page_id":"3tgl2vf85cht","group_id":null}]},{"name":"Service Disruption: Mail Send, API Call \u0026 UI Load Issues","status":"resolved","created_at":"2019-02-14T21:13:54.015-08:00","updated_at":"2019-02-14T21:13:54.941-08:00","monitoring_at":null,"resolved_at":"2019-02-14T21:13:54.100-08:00","impact":"none","shortlink":"http://stspg.io/2c393396b","scheduled_for":null,"scheduled_until":null,"scheduled_remind_prior":false,"scheduled_reminded_at":null,"impact_override":null,"scheduled_auto_in_progress":false,"scheduled_auto_completed":false,"metadata":{},"id":"l3nrdznq8vl1","page_id":"3tgl2vf85cht","incident_updates":[{"status":"resolved","body":"A subset of customers may have experienced issues with mail send, API calls and UI load issues from 8:54 - 9:33PM MST. Our Operations team identified the cause as an issue with a third party service provider. Once this issue was located, our Operations team moved away from this provider for the time being. We apologize for any inconvenience this may have caused. Please contact our Support team at https://support.sendgrid.com with any questions.","created_at":"2019-02-14T21:13:54.100-08:00","wants_twitter_update":true,"twitter_updated_at":"2019-02-14T21:13:54.931-08:00","updated_at":"2019-02-14T21:13:54.937-08:00","display_at":"2019-02-14T21:13:54.100-08:00","affected_components":[{"code":"s0rtr2pvxgzq","name":"Mail Sending - API v3","old_status":"operational","new_status":"operational"},{"code":"nqgmzl9pxtd5","name":"Mail Sending - SMTP","old_status":"operational","new_status":"operational"},{"code":"q6bv40cywctx","name":"Mail Sending - API v2","old_status":"operational","new_status":"operational"},{"code":"4kpfnh9zn3cq","name":"Website","old_status":"operational","new_status":"operational"},{"code":"0srpdt598t37","name":"Marketing Campaigns - Marketing Campaigns","old_status":"operational","new_status":"operational"},{"code":"p9560qrfxy9k","name":"Webhooks - Event Webhook","old_status":"operational","new_status":"operational"},{"code":"9mk6xltks0dj","name":"Webhooks - Parse API","old_status":"operational","new_status":"operational"},{"code":"vgl8t6wflyjt","name":"Other Services - API","old_status":"operational","new_status":"operational"},{"code":"mdt4hw660r2x","name":"Other Services - Email Activity","old_status":"operational","new_status":"operational"}],"deliver_notifications":true,"tweet_id":1096276394055618560,"id":"0td5vy9tkb8d","incident_id":"l3nrdznq8vl1","custom_tweet":null}],"postmortem_body":null,"postmortem_body_last_updated_at":null,"postmortem_ignored":false,"postmortem_published_at":null,"postmortem_notified_subscribers":false,"postmortem_notified_twitter":false,"components":[{"status":"operational","name":"Event Webhook","created_at":"2015-11-18T06:23:17.600-08:00","updated_at":"2018-03-30T16:32:04.940-07:00","position":1,"description":"Real-time POST of event data to a provided URL.","showcase":false,"id":"p9560qrfxy9k","page_id":"3tgl2vf85cht","group_id":"dcvwpwy7361c"},{"status":"operational","name":"API v3","created_at":"2016-05-10T12:44:00.908-07:00","updated_at":"2019-02-18T11:45:40.533-08:00","position":1,"description":"The flow of mail generated by mail.send API requests","showcase":false,"id":"s0rtr2pvxgzq","page_id":"3tgl2vf85cht","group_id":"06b486vkpf0h"},{"status":"operational","name":"API","created_at":"2015-11-18T06:25:16.300-08:00","updated_at":"2019-02-15T12:46:50.796-08:00","position":1,"description":"General API functionality not including mail.send calls.","showcase":false,"id":"vgl8t6wflyjt","page_id":"3tgl2vf85cht","group_id":"zpsd56vwkzvq"},{"status":"operational","name":"Marketing Campaigns","created_at":"2015-11-18T06:21:16.020-08:00","updated_at":"2018-12-22T00:51:27.584-08:00","position":1,"description":"Website, and API access to Marketing Campaigns features.","showcase":false,"id":"0srpdt598t37","page_id":"3tgl2vf85cht","group_id":"1qrzrn1qxjfx"},{"status":"operational","name":"Email Activity","created_at":"2018-05-01T11:04:58.442-07:00","updated_at":"2018-10-30T09:39:19.892-07:00","position":2,"description":null,"showcase":false,"id":"mdt4hw660r2x","page_id":"3tgl2vf85cht","group_id":"zpsd56vwkzvq"},{"status":"operational","name":"SMTP","created_at":"2015-11-18T06:18:39.550-08:00","updated_at":"2019-02-18T11:45:40.551-08:00","position":2,"description":"Mail sent with direct integrations to smtp.sendgrid.net.","showcase":false,"id":"nqgmzl9pxtd5","page_id":"3tgl2vf85cht","group_id":"06b486vkpf0h"},{"status":"operational","name":"Parse API","created_at":"2015-11-18T06:24:07.424-08:00","updated_at":"2018-01-31T14:58:44.134-08:00","position":2,"description":"Inbound mail that is parsed out and POSTed to a provided URL.","showcase":false,"id":"9mk6xltks0dj","page_id":"3tgl2vf85cht","group_id":"dcvwpwy7361c"},{"status":"operational","name":"API v2","created_at":"2015-11-18T06:18:04.353-08:00","updated_at":"2019-02-18T11:45:40.569-08:00","position":3,"description":"The flow of mail generated by mail.send API requests.","showcase":false,"id":"q6bv40cywctx","page_id":"3tgl2vf85cht","group_id":"06b486vkpf0h"},{"status":"operational","name":"Website","created_at":"2015-11-18T06:19:03.286-08:00","updated_at":"2019-02-15T12:46:50.810-08:00","position":5,"description":"Availability of SendGrid.com","showcase":false,"id":"4kpfnh9zn3cq","page_id":"3tgl2vf85cht","group_id":null}]},{"name":"Global Stats Maintenance: Saturday, February 9th, 1PM MST - 12AM MST","status":"completed","created_at":"2019-02-04T14:31:39.318-08:00","updated_at":"2019-02-09T23:10:54.708-08:00","monitoring_at":null,"resolved_at":"2019-02-09T23:09:07.513-08:00","impact":"maintenance","shortlink":"http://stspg.io/413e8f360","scheduled_for":"2019-02-09T12:00:00.000-08:00","scheduled_until":"2019-02-09T22:00:00.000-08:00","scheduled_remind_prior":true,"scheduled_reminded_at":"2019-02-09T10:59:58.788-08:00","impact_override":null,"scheduled_auto_in_progress":true,"scheduled_auto_completed":false,"metadata":{},"id":"th8b8nspdwls","page_id":"3tgl2vf85cht","incident_updates":[{"status":"completed","body":"The scheduled maintenance has been completed. Thank you for your patience during this time.","created_at":"2019-02-09T23:09:07.513-08:00","wants_twitter_update":true,"twitter_updated_at":"2019-02-09T23:09:08.236-08:00","updated_at":"2019-02-09T23:09:08.242-08:00","display_at":"2019-02-09T23:09:07.513-08:00","affected_components":[{"code":"4kpfnh9zn3cq","name":"Website","old_status":"under_maintenance","new_status":"operational"},{"code":"vgl8t6wflyjt","name":"Other Services - API","old_status":"under_maintenance","new_status":"operational"}],"deliver_notifications":true,"tweet_id":1094493451184267264,"id":"rdk8y4q7dd5q","incident_id":"th8b8nspdwls","custom_tweet":null},{"status":"in_progress","body":"The scheduled maintenance is taking longer than expected but almost complete. We're estimating up to 2 more hours for completion. We apologize for the delay and thank you for your patience. Please contact our Support team with any questions at https://support.sendgrid.com/","created_at":"2019-02-09T22:18:53.755-08:00","wants_twitter_update":false,"twitter_updated_at":null,"updated_at":"2019-02-09T22:18:53.755-08:00","display_at":"2019-02-09T22:18:53.755-08:00","affected_components":[{"code":"4kpfnh9zn3cq","name":"Website","old_status":"under_maintenance","new_status":"under_maintenance"},{"code":"vgl8t6wflyjt","name":"Other Services - API","old_status":"under_maintenance","new_status":"under_maintenance"}],"deliver_notifications":true,"tweet_id":null,"id":"2bllptcd30s8","incident_id":"th8b8nspdwls","custom_tweet":null},{"status":"in_progress","body":"The scheduled maintenance is taking longer than expected to complete. We are extending the maintenance window an additional 3 hours till 11PM MST. We apologize for the inconvenience. Please contact our Support team with any questions at https://support.sendgrid.com/","created_at":"2019-02-09T13:25:44.059-08:00","wants_twitter_update":false,"twitter_updated_at":null,"updated_at":"2019-02-09T13:25:44.059-08:00","display_at":"2019-02-09T13:25:44.059-08:00","affected_components":[{"code":"4kpfnh9zn3cq","name":"Website","old_status":"under_maintenance","new_status":"under_maintenance"},{"code":"vgl8t6wflyjt","name":"Other Services - API","old_status":"under_maintenance","new_status":"under_maintenance"}],"deliver_notifications":true,"tweet_id":null,"id":"f88dnply8vgw","incident_id":"th8b8nspdwls","custom_tweet":null},{"status":"in_progress","body":"Scheduled maintenance is currently in progress. We will provide updates as necessary.","created_at":"2019-02-09T12:00:54.231-08:00","wants_twitter_update":false,"twitter_updated_at":null,"updated_at":"2019-02-09T12:00:54.231-08:00","display_at":"2019-02-09T12:00:54.231-08:00","affected_components":[{"code":"4kpfnh9zn3cq","name":"Website","old_status":"operational","new_status":"under_maintenance"},{"code":"vgl8t6wflyjt","name":"Other Services - API","old_status":"operational","new_status":"under_maintenance"}],"deliver_notifications":true,"tweet_id":null,"id":"qbz8p7n3l50h","incident_id":"th8b8nspdwls","custom_tweet":null},{"status":"scheduled","body":"Maintenance will begin as scheduled in 60 minutes.","created_at":"2019-02-09T10:59:58.328-08:00","wants_twitter_update":true,"twitter_updated_at":"2019-02-09T10:59:58.917-08:00","updated_at":"2019-02-09T10:59:58.922-08:00","display_at":"2019-02-09T10:59:58.328-08:00","affected_components":null,"deliver_notifications":true,"tweet_id":1094309953341251585,"id":"yr7qggjjynrv","incident_id":"th8b8nspdwls","custom_tweet":null},{"status":"scheduled","body":"SendGrid Global Statistics service will undergo scheduled maintenance on Saturday, February 9th, between 1PM and 8PM MST. During the maintenance, there will be a delay in global statistics. The stats API will still be available but will not include any new global stats until after the maintenance is complete and global stats are up to date. Please contact our Support team with any questions at https://support.sendgrid.com/","created_at":"2019-02-04T14:31:39.361-08:00","wants_twitter_update":false,"twitter_updated_at":null,"updated_at":"2019-02-04T19:53:52.059-08:00","display_at":"2019-02-04T14:31:39.361-08:00","affected_components":[{"code":"4kpfnh9zn3cq","name":"Website","old_status":"operational","new_status":"operational"},{"code":"vgl8t6wflyjt","name":"Other Services - API","old_status":"operational","new_status":"operational"}],"deliver_notifications":true,"tweet_id":null,"id":"vpynhdhf6vy0","incident_id":"th8b8nspdwls","custom_tweet":null}],"postmortem_body":null,"postmortem_body_last_updated_at":null,"postmortem_ignored":false,"postmortem_published_at":null,"postmortem_notified_subscribers":false,"postmortem_notified_twitter":false,"components":[{"status":"operational","name":"API","created_at":"2015-11-18T06:25:16.300-08:00","updated_at":"2019-02-15T12:46:50.796-08:00","position":1,"description":"General API functionality not including mail.send calls.","showcase":false,"id":"vgl8t6wflyjt","page_id":"3tgl2vf85cht","group_id":"zpsd56vwkzvq"},{"status":"operational","name":"Website","created_at":"2015-11-18T06:19:03.286-08:00","updated_at":"2019-02-15T12:46:50.810-08:00","position":5,"description":"Availability of SendGrid.com","showcase":false,"id":"4kpfnh9zn3cq","page_id":"3tgl2vf85cht","group_id":null}]},{"name":"Service Disruption: Mail Delays","status":"resolved","created_at":"2019-02-02T09:09:14.948-08:00","updated_at":"2019-02-02T10:34:55.672-08:00","monitoring_at":"2019-02-02T10:05:59.096-08:00","resolved_at":"2019-02-02T10:34:55.133-08:00","impact":"major","shortlink":"http://stspg.io/55e87b81e","scheduled_for":null,"scheduled_until":null,"scheduled_remind_prior":false,"scheduled_reminded_at":null,"impact_override":null,"scheduled_auto_in_progress":false,"scheduled_auto_completed":false,"metadata":{},"id":"v80113h5txql","page_id":"3tgl2vf85cht","incident_updates":[{"status":"resolved","body":"After monitoring, we can confirm that the source of the disruption has been fixed and associated mail delays have been resolved. We sincerely apologize for any inconvenience this issue may have caused and greatly appreciate your patience. Please contact our Support team at https://support.sendgrid.com with any questions.","created_at":"2019-02-02T10:34:55.133-08:00","wants_twitter_update":true,"twitter_updated_at":"2019-02-02T10:34:55.659-08:00","updated_at":"2019-02-02T10:34:55.667-08:00","display_at":"2019-02-02T10:34:55.133-08:00","affected_components":[{"code":"s0rtr2pvxgzq","name":"Mail Sending - API v3","old_status":"partial_outage","new_status":"operational"},{"code":"nqgmzl9pxtd5","name":"Mail Sending - SMTP","old_status":"partial_outage","new_status":"operational"},{"code":"q6bv40cywctx","name":"Mail Sending - API v2","old_status":"partial_outage","new_status":"operational"}],"deliver_notifications":true,"tweet_id":1091766932989128704,"id":"w69g5rbwqjkn","incident_id":"v80113h5txql","custom_tweet":null},{"status":"monitoring","body":"Our Operations team has identified the source of these delays and implemented a fix. Delayed messages associated with this disruption have been sent and sending queues have returned to normal levels. We are continuing to monitor the situation and will provide an update when we can confirm the issue has been resolved. We apologize for any inconvenience this caused you. Please contact our Support team with any questions at https://support.sendgrid.com","created_at":"2019-02-02T10:05:59.096-08:00","wants_twitter_update":true,"twitter_updated_at":"2019-02-02T10:05:59.743-08:00","updated_at":"2019-02-02T10:28:37.847-08:00","display_at":"2019-02-02T10:05:59.096-08:00","affected_components":[{"code":"s0rtr2pvxgzq","name":"Mail Sending - API v3","old_status":"partial_outage","new_status":"partial_outage"},{"code":"nqgmzl9pxtd5","name":"Mail Sending - SMTP","old_status":"partial_outage","new_status":"partial_outage"},{"code":"q6bv40cywctx","name":"Mail Sending - API v2","old_status":"partial_outage","new_status":"partial_outage"}],"deliver_notifications":true,"tweet_id":1091759652063203328,"id":"4x7lv7srsvf9","incident_id":"v80113h5txql","custom_tweet":null},{"status":"investigating","body":"Our Operations team is continuing to investigate delayed mail for a subset of our customers. We will provide further updates as soon as we have more information. Please reach out to our Support team with any questions at https://support.sendgrid.com","created_at":"2019-02-02T09:45:43.148-08:00","wants_twitter_update":true,"twitter_updated_at":"2019-02-02T09:45:43.725-08:00","updated_at":"2019-02-02T09:45:43.728-08:00","display_at":"2019-02-02T09:45:43.148-08:00","affected_components":[{"code":"s0rtr2pvxgzq","name":"Mail Sending - API v3","old_status":"partial_outage","new_status":"partial_outage"},{"code":"nqgmzl9pxtd5","name":"Mail Sending - SMTP","old_status":"partial_outage","new_status":"partial_outage"},{"code":"q6bv40cywctx","name":"Mail Sending - API v2","old_status":"partial_outage","new_status":"partial_outage"}],"deliver_notifications":true,"tweet_id":1091754551722438657,"id":"0t52d88gv6z1","incident_id":"v80113h5txql","custom_tweet":null},{"status":"investigating","body":"A subset of our customers may be experiencing delayed mail starting at approximately 10:00pm MST, on February 1st, 2019. Our operations team is investigating the cause of this issue and we will provide updates here as they become available. We apologize for any inconvenience this may be causing you. Please contact our Support team at https://support.sendgrid.com with any questions.","created_at":"2019-02-02T09:09:15.104-08:00","wants_twitter_update":true,"twitter_updated_at":"2019-02-02T09:09:15.625-08:00","updated_at":"2019-02-02T09:09:15.630-08:00","display_at":"2019-02-02T09:09:15.104-08:00","affected_components":[{"code":"s0rtr2pvxgzq","name":"Mail Sending - API v3","old_status":"operational","new_status":"partial_outage"},{"code":"nqgmzl9pxtd5","name":"Mail Sending - SMTP","old_status":"operational","new_status":"partial_outage"},{"code":"q6bv40cywctx","name":"Mail Sending - API v2","old_status":"operational","new_status":"partial_outage"}],"deliver_notifications":true,"tweet_id":1091745374153322496,"id":"rkrtys8wmsgw","incident_id":"v80113h5txql","custom_tweet":null}],"postmortem_body":null,"postmortem_body_last_updated_at":null,"postmortem_ignored":false,"postmortem_published_at":null,"postmortem_notified_subscribers":false,"postmortem_notified_twitter":false,"components":[{"status":"operational","name":"API v3","created_at":"2016-05-10T12:44:00.908-07:00","updated_at":"2019-02-18T11:45:40.533-08:00","position":1,"description":"The flow of mail generated by mail.send API requests","showcase":false,"id":"s0rtr2pvxgzq","page_id":"3tgl2vf85cht","group_id":"06b486vkpf0h"},{"status":"operational","name":"SMTP","created_at":"2015-11-18T06:18:39.550-08:00","updated_at":"2019-02-18T11:45:40.551-08:00","position":2,"description":"Mail sent with direct integrations to smtp.sendgrid.net.","showcase":false,"id":"nqgmzl9pxtd5","page_id":"3tgl2vf85cht","group_id":"06b486vkpf0h"},{"status":"operational","name":"API v2","created_at":"2015-11-18T06:18:04.353-08:00","updated_at":"2019-02-18T11:45:40.569-08:00","position":3,"description":"The flow of mail generated by mail.send API requests.","showcase":false,"id":"q6bv40cywctx","page_id":"3tgl2vf85cht","group_id":"06b486vkpf0h"}]},{"name":"Technical Incident: Mail Delay","status":"resolved","created_at":"2019-01-31T03:25:13.440-08:00","updated_at":"2019-01-31T04:21:16.848-08:00","monitoring_at":"2019-01-31T03:49:09.296-08:00","resolved_at":"2019-01-31T04:21:16.219-08:00","impact":"none","shortlink":"http://stspg.io/0d5e4c013","scheduled_for":null,"scheduled_until":null,"scheduled_remind_prior":false,"scheduled_reminded_at":null,"impact_override":null,"scheduled_auto_in_progress":false,"scheduled_auto_completed":false,"metadata":{},"id":"by45gtdxfq9t","page_id":"3tgl2vf85cht","incident_updates":[{"status":"resolved","body":"After monitoring the fix, our Operations team has confirmed sending queues have returned to normal levels. We greatly appreciate your patience through this process and apologize for any inconvenience this may have caused you. Please reach out to our Support team at https://support.sendgrid.com/ with any questions.","created_at":"2019-01-31T04:21:16.219-08:00","wants_twitter_update":true,"twitter_updated_at":"2019-01-31T04:21:16.833-08:00","updated_at":"2019-01-31T04:21:16.844-08:00","display_at":"2019-01-31T04:21:16.219-08:00","affected_components":[{"code":"s0rtr2pvxgzq","name":"Mail Sending - API v3","old_status":"operational","new_status":"operational"},{"code":"nqgmzl9pxtd5","name":"Mail Sending - SMTP","old_status":"operational","new_status":"operational"},{"code":"q6bv40cywctx","name":"Mail Sending - API v2","old_status":"operational","new_status":"operational"}],"deliver_notifications":true,"tweet_id":1090948125609455618,"id":"7zzds5z5nwx4","incident_id":"by45gtdxfq9t","custom_tweet":null},{"status":"monitoring","body":"After investigating the issue, our Operations team has implemented a fix and is monitoring sending queues as they return to normal levels. We apologize for any inconvenience this may have caused you. Please contact our Support team https://support.sendgrid.com/ with any questions.","created_at":"2019-01-31T03:49:09.296-08:00","wants_twitter_update":true,"twitter_updated_at":"2019-01-31T03:49:10.117-08:00","updated_at":"2019-01-31T03:49:10.120-08:00","display_at":"2019-01-31T03:49:09.296-08:00","affected_components":[{"code":"s0rtr2pvxgzq","name":"Mail Sending - API v3","old_status":"operational","new_status":"operational"},{"code":"nqgmzl9pxtd5","name":"Mail Sending - SMTP","old_status":"operational","new_status":"operational"},{"code":"q6bv40cywctx","name":"Mail Sending - API v2","old_status":"operational","new_status":"operational"}],"deliver_notifications":true,"tweet_id":1090940044653686785,"id":"njdjxc5r3ht6","incident_id":"by45gtdxfq9t","custom_tweet":null},{"status":"investigating","body":"A small subset of our customers may be experiencing delayed mail beginning at approximately 12:00 AM MST. Our Operations team is investigating the root cause of this issue. We apologize for any inconvenience this may be causing and will provide updates as they become available. Please contact our Support team https://support.sendgrid.com/ with any questions.","created_at":"2019-01-31T03:25:13.487-08:00","wants_twitter_update":true,"twitter_updated_at":"2019-01-31T03:25:14.626-08:00","updated_at":"2019-01-31T03:25:14.630-08:00","display_at":"2019-01-31T03:25:13.487-08:00","affected_components":[{"code":"s0rtr2pvxgzq","name":"Mail Sending - API v3","old_status":"operational","new_status":"operational"},{"code":"nqgmzl9pxtd5","name":"Mail Sending - SMTP","old_status":"operational","new_status":"operational"},{"code":"q6bv40cywctx","name":"Mail Sending - API v2","old_status":"operational","new_status":"operational"}],"deliver_notifications":true,"tweet_id":1090934023570972672,"id":"j3pxbffv16br","incident_id":"by45gtdxfq9t","custom_tweet":null}],"postmortem_body":null,"postmortem_body_last_updated_at":null,"postmortem_ignored":false,"postmortem_published_at":null,"postmortem_notified_subscribers":false,"postmortem_notified_twitter":false,"components":[{"status":"operational","name":"API v3","created_at":"2016-05-10T12:44:00.908-07:00","updated_at":"2019-02-18T11:45:40.533-08:00","position":1,"description":"The flow of mail generated by mail.send API requests","showcase":false,"id":"s0rtr2pvxgzq","page_id":"3tgl2vf85cht","group_id":"06b486vkpf0h"},{"status":"operational","name":"SMTP","created_at":"2015-11-18T06:18:39.550-08:00","updated_at":"2019-02-18T11:45:40.551-08:00","position":2,"description":"Mail sent with direct integrations to smtp.sendgrid.net.","showcase":false,"id":"nqgmzl9pxtd5","page_id":"3tgl2vf85cht","group_id":"06b486vkpf0h"},{"status":"operational","name":"API v2","created_at":"2015-11-18T06:18:04.353-08:00","updated_at":"2019-02-18T11:45:40.569-08:00","position":3,"description":"The flow of mail generated by mail.send API requests.","showcase":false,"id":"q6bv40cywctx","page_id":"3tgl2vf85cht","group_id":"06b486vkpf0h"}]},{"name":"Technical Incident: Errors connecting to smtp.sendgrid.net","status":"resolved","created_at":"2019-01-23T12:34:48.679-08:00","updated_at":"2019-01-23T13:32:33.247-08:00","monitoring_at":null,"resolved_at":"2019-01-23T13:32:32.106-08:00","impact":"minor","shortlink":"http://stspg.io/c2896f770","scheduled_for":null,"scheduled_until":null,"scheduled_remind_prior":false,"scheduled_reminded_at":null,"impact_override":null,"scheduled_auto_in_progress":false,"scheduled_auto_completed":false,"metadata":{},"id":"82qj3fv02yx4","page_id":"3tgl2vf85cht","incident_updates":[{"status":"resolved","body":"Our operations team has resolved the errors connecting to smtp.sendgrid.net. Again, we apologize for any inconvenience this caused. If you have questions please contact our Support team: https://support.sendgrid.com/.","created_at":"2019-01-23T13:32:32.106-08:00","wants_twitter_update":true,"twitter_updated_at":"2019-01-23T13:32:33.233-08:00","updated_at":"2019-01-23T13:32:33.242-08:00","display_at":"2019-01-23T13:32:32.106-08:00","affected_components":[{"code":"nqgmzl9pxtd5","name":"Mail Sending - SMTP","old_status":"degraded_performance","new_status":"operational"}],"deliver_notifications":true,"tweet_id":1088187754872168448,"id":"ztc0fyx6rs50","incident_id":"82qj3fv02yx4","custom_tweet":null},{"status":"identified","body":"Starting around 11:15am MT, a subset of customers reported errors connecting to smtp.sendgrid.net. Our operations team is working to resolve these errors. We’ll provide updates as we have information to share. We apologize for any inconvenience this caused you. Please contact our Support team with any questions: https://support.sendgrid.com/.","created_at":"2019-01-23T12:34:48.767-08:00","wants_twitter_update":true,"twitter_updated_at":"2019-01-23T12:34:49.322-08:00","updated_at":"2019-01-23T12:34:49.325-08:00","display_at":"2019-01-23T12:34:48.767-08:00","affected_components":[{"code":"nqgmzl9pxtd5","name":"Mail Sending - SMTP","old_status":"operational","new_status":"degraded_performance"}],"deliver_notifications":true,"tweet_id":1088173226797150208,"id":"lhb5dgytq9bv","incident_id":"82qj3fv02yx4","custom_tweet":null}],"postmortem_body":null,"postmortem_body_last_updated_at":null,"postmortem_ignored":false,"postmortem_published_at":null,"postmortem_notified_subscribers":false,"postmortem_notified_twitter":false,"components":[{"status":"operational","name":"SMTP","created_at":"2015-11-18T06:18:39.550-08:00","updated_at":"2019-02-18T11:45:40.551-08:00","position":2,"description":"Mail sent with direct integrations to smtp.sendgrid.net.","showcase":false,"id":"nqgmzl9pxtd5","page_id":"3tgl2vf85cht","group_id":"06b486vkpf0h"}]},{"name":"Service Disruption: Site and Login Availability","status":"resolved","created_at":"2019-01-04T02:38:04.460-08:00","updated_at":"2019-01-04T02:55:37.752-08:00","monitoring_at":null,"resolved_at":"2019-01-04T02:55:37.250-08:00","impact":"minor","shortlink":"http://stspg.io/122b8ad04","scheduled_for":null,"scheduled_until":null,"scheduled_remind_prior":false,"scheduled_reminded_at":null,"impact_override":null,"scheduled_auto_in_progress":false,"scheduled_auto_completed":false,"metadata":{},"id":"v7v3tjmm323n","page_id":"3tgl2vf85cht","incident_updates":[{"status":"resolved","body":"Our Operations team was able to locate and resolve the issue. Users should now be able to login and access app.sendgrid.com without latency or errors. We apologize for any inconvenience this may have caused and thank you for your patience. If you have questions, please contact our Support team at https://support.sendgrid.com/.","created_at":"2019-01-04T02:55:37.250-08:00","wants_twitter_update":true,"twitter_updated_at":"2019-01-04T02:55:37.747-08:00","updated_at":"2019-01-04T02:55:37.750-08:00","display_at":"2019-01-04T02:55:37.250-08:00","affected_components":[{"code":"4kpfnh9zn3cq","name":"Website","old_status":"degraded_performance","new_status":"operational"}],"deliver_notifications":true,"tweet_id":1081142098336731137,"id":"xrtf591y1lq3","incident_id":"v7v3tjmm323n","custom_tweet":null},{"status":"investigating","body":"Starting around 1:20 AM MT, we received reports from users experiencing latency and errors when loading app.sendgrid.com, mainly in Europe and Asia Pacific. Our Operations team is investigating the situation and working towards a resolution. We apologize for any inconvenience this may cause. If you have questions, please contact our Support team at https://support.sendgrid.com/.","created_at":"2019-01-04T02:38:04.540-08:00","wants_twitter_update":true,"twitter_updated_at":"2019-01-04T02:38:05.119-08:00","updated_at":"2019-01-04T02:38:05.123-08:00","display_at":"2019-01-04T02:38:04.540-08:00","affected_components":[{"code":"4kpfnh9zn3cq","name":"Website","old_status":"operational","new_status":"degraded_performance"}],"deliver_notifications":true,"tweet_id":1081137683538165762,"id":"1jwnkv9wzqg9","incident_id":"v7v3tjmm323n","custom_tweet":null}],"postmortem_body":null,"postmortem_body_last_updated_at":null,"postmortem_ignored":false,"postmortem_published_at":null,"postmortem_notified_subscribers":false,"postmortem_notified_twitter":false,"components":[{"status":"operational","name":"Website","created_at":"2015-11-18T06:19:03.286-08:00","updated_at":"2019-02-15T12:46:50.810-08:00","position":5,"description":"Availability of SendGrid.com","showcase":false,"id":"4kpfnh9zn3cq","page_id":"3tgl2vf85cht","group_id":null}]},{"name":"Service Outage - Paid Signup and Credit Card Modification","status":"resolved","created_at":"2019-01-03T11:11:23.074-08:00","updated_at":"2019-01-03T14:57:29.566-08:00","monitoring_at":"2019-01-03T11:11:23.133-08:00","resolved_at":"2019-01-03T14:57:28.479-08:00","impact":"none","shortlink":"http://stspg.io/c87d804d2","scheduled_for":null,"scheduled_until":null,"scheduled_remind_prior":false,"scheduled_reminded_at":null,"impact_override":null,"scheduled_auto_in_progress":false,"scheduled_auto_completed":false,"metadata":{},"id":"jgykw2zkk619","page_id":"3tgl2vf85cht","incident_updates":[{"status":"resolved","body":"Our Operations team has reverted to our primary billing provider at this time. We appreciate your patience and apologize for any inconvenience this may have caused. Please reach out to our Support team at https://support.sendgrid.com if you have any questions.","created_at":"2019-01-03T14:57:28.479-08:00","wants_twitter_update":true,"twitter_updated_at":"2019-01-03T14:57:29.533-08:00","updated_at":"2019-01-03T14:57:29.546-08:00","display_at":"2019-01-03T14:57:28.479-08:00","affected_components":[{"code":"4kpfnh9zn3cq","name":"Website","old_status":"operational","new_status":"operational"}],"deliver_notifications":true,"tweet_id":1080961373532614656,"id":"wvtxfzb86wt8","incident_id":"jgykw2zkk619","custom_tweet":null},{"status":"monitoring","body":"Our Operations team is continuing to monitor the situation until we revert to our primary provider. We will provide another update when this is complete. Please reach out to our Support team with any questions at https://support.sendgrid.com/","created_at":"2019-01-03T13:24:38.396-08:00","wants_twitter_update":true,"twitter_updated_at":"2019-01-03T13:24:39.059-08:00","updated_at":"2019-01-03T13:24:39.063-08:00","display_at":"2019-01-03T13:24:38.396-08:00","affected_components":[{"code":"4kpfnh9zn3cq","name":"Website","old_status":"operational","new_status":"operational"}],"deliver_notifications":true,"tweet_id":1080938009300688897,"id":"9tcf48m94pnx","incident_id":"jgykw2zkk619","custom_tweet":null},{"status":"monitoring","body":"Beginning at 10:03AM MST, one of our third party billing providers began experiencing an issue which caused paid signups and changes to credit card information to be unavailable on our platform. At 11:34 AM MST, SendGrid moved to our backup provider, which made paid signups and credit card changes available again. If you experienced any issues when attempting to sign up for a new account during this window, please try again at this time. We apologize for any inconvenience this may have caused and are continuing to monitor the situation until we revert to our primary provider. Please reach out to our Support team with any questions at https://support.sendgrid.com/","created_at":"2019-01-03T11:11:23.133-08:00","wants_twitter_update":true,"twitter_updated_at":"2019-01-03T11:11:23.668-08:00","updated_at":"2019-01-03T11:25:14.310-08:00","display_at":"2019-01-03T11:11:23.133-08:00","affected_components":[{"code":"4kpfnh9zn3cq","name":"Website","old_status":"operational","new_status":"operational"}],"deliver_notifications":true,"tweet_id":1080904474288246784,"id":"dj1nt46ndv1f","incident_id":"jgykw2zkk619","custom_tweet":null}],"postmortem_body":null,"postmortem_body_last_updated_at":null,"postmortem_ignored":false,"postmortem_published_at":null,"postmortem_notified_subscribers":false,"postmortem_notified_twitter":false,"components":[{"status":"operational","name":"Website","created_at":"2015-11-18T06:19:03.286-08:00","updated_at":"2019-02-15T12:46:50.810-08:00","position":5,"description":"Availability of SendGrid.com","showcase":false,"id":"4kpfnh9zn3cq","page_id":"3tgl2vf85cht","group_id":null}]},{"name":"Service Disruption: Inflated Unique Engagement Stats","status":"resolved","created_at":"2018-12-22T09:20:16.793-08:00","updated_at":"2018-12-22T09:21:09.082-08:00","monitoring_at":null,"resolved_at":"2018-12-22T09:20:16.864-08:00","impact":"none","shortlink":"http://stspg.io/fd61e53e2","scheduled_for":null,"scheduled_until":null,"scheduled_remind_prior":false,"scheduled_reminded_at":null,"impact_override":null,"scheduled_auto_in_progress":false,"scheduled_auto_completed":false,"metadata":{},"id":"2rsctp0xwzyn","page_id":"3tgl2vf85cht","incident_updates":[{"status":"resolved","body":"Around 11:15AM MT on December 21st, one of our system’s cache holding data for the uniqueness of engagement stats was disrupted, causing a small portion of unique engagement stats to appear inflated. This impacted unique open and click statistics in the Stats API and our Stats pages in your account. Event Webhook data was not affected.\n\nOur team has replayed the uniqueness data and have restored the correct levels of uniqueness for the effected open and click stats. We sincerely apologize for any inconvenience this may have caused. If you have any questions, you can contact Support at https://support.sendgrid.com.","created_at":"2018-12-22T09:20:16.864-08:00","wants_twitter_update":true,"twitter_updated_at":"2018-12-22T09:20:17.622-08:00","updated_at":"2018-12-22T09:20:17.627-08:00","display_at":"2018-12-22T09:20:16.864-08:00","affected_components":[{"code":"s0rtr2pvxgzq","name":"Mail Sending - API v3","old_status":"operational","new_status":"operational"},{"code":"nqgmzl9pxtd5","name":"Mail Sending - SMTP","old_status":"operational","new_status":"operational"},{"code":"q6bv40cywctx","name":"Mail Sending - API v2","old_status":"operational","new_status":"operational"},{"code":"4kpfnh9zn3cq","name":"Website","old_status":"operational","new_status":"operational"},{"code":"0srpdt598t37","name":"Marketing Campaigns - Marketing Campaigns","old_status":"operational","new_status":"operational"},{"code":"p9560qrfxy9k","name":"Webhooks - Event Webhook","old_status":"operational","new_status":"operational"},{"code":"9mk6xltks0dj","name":"Webhooks - Parse API","old_status":"operational","new_status":"operational"},{"code":"vgl8t6wflyjt","name":"Other Services - API","old_status":"operational","new_status":"operational"},{"code":"mdt4hw660r2x","name":"Other Services - Email Activity","old_status":"operational","new_status":"operational"}],"deliver_notifications":true,"tweet_id":1076527860490199040,"id":"vwvpb13qp1zm","incident_id":"2rsctp0xwzyn","custom_tweet":null}],"postmortem_body":null,"postmortem_body_last_updated_at":null,"postmortem_ignored":false,"postmortem_published_at":null,"postmortem_notified_subscribers":false,"postmortem_notified_twitter":false,"components":[{"status":"operational","name":"Event Webhook","created_at":"2015-11-18T06:23:17.600-08:00","updated_at":"2018-03-30T16:32:04.940-07:00","position":1,"description":"Real-time POST of event data to a provided URL.","showcase":false,"id":"p9560qrfxy9k","page_id":"3tgl2vf85cht","group_id":"dcvwpwy7361c"},{"status":"operational","name":"API v3","created_at":"2016-05-10T12:44:00.908-07:00","updated_at":"2019-02-18T11:45:40.533-08:00","position":1,"description":"The flow of mail generated by mail.send API requests","showcase":false,"id":"s0rtr2pvxgzq","page_id":"3tgl2vf85cht","group_id":"06b486vkpf0h"},{"status":"operational","name":"API","created_at":"2015-11-18T06:25:16.300-08:00","updated_at":"2019-02-15T12:46:50.796-08:00","position":1,"description":"General API functionality not including mail.send calls.","showcase":false,"id":"vgl8t6wflyjt","page_id":"3tgl2vf85cht","group_id":"zpsd56vwkzvq"},{"status":"operational","name":"Marketing Campaigns","created_at":"2015-11-18T06:21:16.020-08:00","updated_at":"2018-12-22T00:51:27.584-08:00","position":1,"description":"Website, and API access to Marketing Campaigns features.","showcase":false,"id":"0srpdt598t37","page_id":"3tgl2vf85cht","group_id":"1qrzrn1qxjfx"},{"status":"operational","name":"Email Activity","created_at":"2018-05-01T11:04:58.442-07:00","updated_at":"2018-10-30T09:39:19.892-07:00","position":2,"description":null,"showcase":false,"id":"mdt4hw660r2x","page_id":"3tgl2vf85cht","group_id":"zpsd56vwkzvq"},{"status":"operational","name":"SMTP","created_at":"2015-11-18T06:18:39.550-08:00","updated_at":"2019-02-18T11:45:40.551-08:00","position":2,"description":"Mail sent with direct integrations to smtp.sendgrid.net.","showcase":false,"id":"nqgmzl9pxtd5","page_id":"3tgl2vf85cht","group_id":"06b486vkpf0h"},{"status":"operational","name":"Parse API","created_at":"2015-11-18T06:24:07.424-08:00","updated_at":"2018-01-31T14:58:44.134-08:00","position":2,"description":"Inbound mail that is parsed out and POSTed to a provided URL.","showcase":false,"id":"9mk6xltks0dj","page_id":"3tgl2vf85cht","group_id":"dcvwpwy7361c"},{"status":"operational","name":"API v2","created_at":"2015-11-18T06:18:04.353-08:00","updated_at":"2019-02-18T11:45:40.569-08:00","position":3,"description":"The flow of mail generated by mail.send API requests.","showcase":false,"id":"q6bv40cywctx","page_id":"3tgl2vf85cht","group_id":"06b486vkpf0h"},{"status":"operational","name":"Website","created_at":"2015-11-18T06:19:03.286-08:00","updated_at":"2019-02-15T12:46:50.810-08:00","position":5,"description":"Availability of SendGrid.com","showcase":false,"id":"4kpfnh9zn3cq","page_id":"3tgl2vf85cht","group_id":null}]}]
 }`
 
+func newServer(statusCode int, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/status.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+func healthChecks(server *httptest.Server, options ...statuspage.Option) health.Checks {
+	options = append([]statuspage.Option{statuspage.WithBaseURL(server.URL), statuspage.WithHTTPClient(server.Client())}, options...)
+	checks := Health(options...).HealthChecks()
+	return checks["SendGrid"][0]
+}
+
 func TestSendGrid_HealthChecks(t *testing.T) {
-	sendGrid := sendGrid{}
-	checks := sendGrid.HealthChecks()
-	_, _ = fmt.Fprintf(os.Stderr, "%v\n", checks)
+	server := newServer(http.StatusOK, sampleResponse)
+	defer server.Close()
+	checks := healthChecks(server)
+	_ = assert.Equals(t, health.Pass, checks.Status)
+	_ = assert.Equals(t, "", checks.Output)
+	_ = assert.True(t, checks.Time != "")
+}
+
+func TestSendGrid_Indicators(t *testing.T) {
+	for _, tc := range []struct {
+		indicator   string
+		description string
+		status      health.Status
+		output      string
+	}{
+		{"none", "All Systems Operational", health.Pass, ""},
+		{"minor", "Minor Service Outage", health.Warn, "Minor Service Outage"},
+		{"major", "Partial System Outage", health.Warn, "Partial System Outage"},
+		{"critical", "Major System Outage", health.Fail, "Major System Outage"},
+		{"maintenance", "Service Under Maintenance", health.Fail, "Service Under Maintenance"},
+		{"maintenance", "", health.Fail, `could not get description from SendGrid: unknown indicator "maintenance"`},
+	} {
+		server := newServer(http.StatusOK, `{"status": {"indicator": "`+tc.indicator+`", "description": "`+tc.description+`"}}`)
+		checks := healthChecks(server)
+		server.Close()
+		_ = assert.Equals(t, tc.status, checks.Status)
+		_ = assert.Equals(t, tc.output, checks.Output)
+	}
+}
+
+func TestSendGrid_IndicatorMapping(t *testing.T) {
+	server := newServer(http.StatusOK, `{"status": {"indicator": "major", "description": "Partial System Outage"}}`)
+	defer server.Close()
+	checks := healthChecks(server, statuspage.WithIndicatorStatus("major", health.Fail))
+	_ = assert.Equals(t, health.Fail, checks.Status)
+}
+
+func TestSendGrid_Malformed(t *testing.T) {
+	for _, tc := range []struct {
+		statusCode int
+		body       string
+		output     string
+	}{
+		{http.StatusOK, ``, "could not parse response from SendGrid: EOF"},
+		{http.StatusOK, `<html>`, "could not parse response from SendGrid: invalid character '<' looking for beginning of value"},
+		{http.StatusOK, `{"page": {"name": "SendGrid"}}`, "could not parse response from SendGrid: no status"},
+		{http.StatusOK, `{"status": "none"}`, "could not parse response from SendGrid: json: cannot unmarshal string"},
+		{http.StatusInternalServerError, sampleResponse, "could not get status from SendGrid: HTTP status code 500"},
+	} {
+		server := newServer(tc.statusCode, tc.body)
+		checks := healthChecks(server)
+		server.Close()
+		_ = assert.Equals(t, health.Fail, checks.Status)
+		_ = assert.True(t, strings.HasPrefix(checks.Output, tc.output))
+	}
+}
+
+func TestSendGrid_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		_, _ = w.Write([]byte(sampleResponse))
+	}))
+	defer server.Close()
+	checks := healthChecks(server, statuspage.WithTimeout(10*time.Millisecond))
+	_ = assert.Equals(t, health.Fail, checks.Status)
+	_ = assert.True(t, strings.Contains(checks.Output, "context deadline exceeded"))
+}
+
+func TestSendGrid_Unreachable(t *testing.T) {
+	server := newServer(http.StatusOK, sampleResponse)
+	server.Close()
+	checks := healthChecks(server)
+	_ = assert.Equals(t, health.Fail, checks.Status)
 }
@@ -0,0 +1,84 @@
+// Package sqldb provides health checks for a database/sql connection pool.
+// This works with any database/sql driver, like PostgreSQL, MySQL or SQLite.
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"github.com/nelkinda/health-go"
+	"net/http"
+	"time"
+)
+
+type sqlDB struct {
+	name        string
+	componentID string
+	db          *sql.DB
+	timeout     time.Duration
+	threshold   time.Duration
+}
+
+func (s *sqlDB) HealthChecks() map[string][]health.Checks {
+	return s.HealthChecksContext(context.Background())
+}
+
+func (s *sqlDB) HealthChecksContext(ctx context.Context) map[string][]health.Checks {
+	start := time.Now().UTC()
+	startTime := start.Format(time.RFC3339Nano)
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	err := s.db.PingContext(ctx)
+	var checks = health.Checks{
+		ComponentID:   s.componentID,
+		ComponentType: "datastore",
+		Time:          startTime,
+	}
+	if err != nil {
+		checks.Output = err.Error()
+		checks.Status = health.Fail
+	} else {
+		end := time.Now().UTC()
+		responseTime := end.Sub(start)
+		checks.ObservedValue = responseTime.Nanoseconds()
+		checks.ObservedUnit = "ns"
+		if responseTime > s.threshold {
+			checks.Status = health.Warn
+		} else {
+			checks.Status = health.Pass
+		}
+	}
+	stats := s.db.Stats()
+	connections := func(componentID string, value interface{}, unit string) health.Checks {
+		return health.Checks{
+			ComponentID:   componentID,
+			ComponentType: "datastore",
+			ObservedValue: value,
+			ObservedUnit:  unit,
+			Status:        health.Pass,
+			Time:          startTime,
+		}
+	}
+	return map[string][]health.Checks{
+		s.name + ":responseTime": {checks},
+		s.name + ":connections": {
+			connections("Max Open", stats.MaxOpenConnections, ""),
+			connections("Open", stats.OpenConnections, ""),
+			connections("In Use", stats.InUse, ""),
+			connections("Idle", stats.Idle, ""),
+			connections("Wait Count", stats.WaitCount, ""),
+			connections("Wait Duration", stats.WaitDuration.Nanoseconds(), "ns"),
+		},
+	}
+}
+
+func (*sqlDB) AuthorizeHealth(*http.Request) bool {
+	return true
+}
+
+// Health returns a ChecksProvider for health checks about a database/sql connection pool.
+// The response time of pinging the database is reported under "<name>:responseTime", with the given componentID.
+// It fails if pinging fails or takes longer than timeout, and warns if it takes longer than threshold.
+// The connection pool statistics from sql.DBStats are reported under "<name>:connections".
+func Health(name string, componentID string, db *sql.DB, timeout time.Duration, threshold time.Duration) health.ChecksProvider {
+	return &sqlDB{name: name, componentID: componentID, db: db, timeout: timeout, threshold: threshold}
+}
@@ -0,0 +1,105 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"github.com/christianhujer/assert"
+	"github.com/nelkinda/health-go"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stub is an in-process database/sql driver whose ping can be delayed or fail.
+type stub struct {
+	mutex sync.Mutex
+	delay time.Duration
+	err   error
+}
+
+func (s *stub) set(delay time.Duration, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.delay, s.err = delay, err
+}
+
+func (s *stub) Open(string) (driver.Conn, error) {
+	return &stubConn{s}, nil
+}
+
+type stubConn struct {
+	driver *stub
+}
+
+func (c *stubConn) Ping(ctx context.Context) error {
+	c.driver.mutex.Lock()
+	delay, err := c.driver.delay, c.driver.err
+	c.driver.mutex.Unlock()
+	select {
+	case <-time.After(delay):
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (*stubConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (*stubConn) Close() error {
+	return nil
+}
+
+func (*stubConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+var stubDriver = &stub{}
+
+func init() {
+	sql.Register("stub", stubDriver)
+}
+
+func TestHealth(t *testing.T) {
+	db, err := sql.Open("stub", "")
+	_ = assert.Nil(t, err)
+	defer func() { _ = db.Close() }()
+	db.SetMaxOpenConns(5)
+	provider := Health("postgres", "postgres://localhost/orders", db, 100*time.Millisecond, 20*time.Millisecond)
+	_ = assert.True(t, provider.AuthorizeHealth(nil))
+
+	stubDriver.set(0, nil)
+	checks := provider.HealthChecks()
+	responseTime := checks["postgres:responseTime"][0]
+	_ = assert.Equals(t, health.Pass, responseTime.Status)
+	_ = assert.Equals(t, "postgres://localhost/orders", responseTime.ComponentID)
+	_ = assert.Equals(t, "datastore", responseTime.ComponentType)
+	_ = assert.Equals(t, "ns", responseTime.ObservedUnit)
+	connections := checks["postgres:connections"]
+	_ = assert.Equals(t, 6, len(connections))
+	_ = assert.Equals(t, "Max Open", connections[0].ComponentID)
+	_ = assert.Equals(t, 5, connections[0].ObservedValue)
+	_ = assert.Equals(t, "Open", connections[1].ComponentID)
+	_ = assert.Equals(t, 1, connections[1].ObservedValue)
+	_ = assert.Equals(t, "Idle", connections[3].ComponentID)
+	_ = assert.Equals(t, 1, connections[3].ObservedValue)
+	_ = assert.Equals(t, "ns", connections[5].ObservedUnit)
+
+	stubDriver.set(40*time.Millisecond, nil)
+	checks = provider.HealthChecks()
+	_ = assert.Equals(t, health.Warn, checks["postgres:responseTime"][0].Status)
+
+	stubDriver.set(time.Second, nil)
+	checks = provider.HealthChecks()
+	_ = assert.Equals(t, health.Fail, checks["postgres:responseTime"][0].Status)
+	_ = assert.Equals(t, context.DeadlineExceeded.Error(), checks["postgres:responseTime"][0].Output)
+
+	stubDriver.set(0, errors.New("connection refused"))
+	checks = provider.HealthChecks()
+	_ = assert.Equals(t, health.Fail, checks["postgres:responseTime"][0].Status)
+	_ = assert.Equals(t, "connection refused", checks["postgres:responseTime"][0].Output)
+	_ = assert.Nil(t, checks["postgres:responseTime"][0].ObservedValue)
+}
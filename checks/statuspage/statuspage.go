@@ -0,0 +1,227 @@
+// Package statuspage provides health checks for vendors which publish their status with Statuspage, like GitHub, Twilio, Atlassian, Datadog or SendGrid.
+// The overall status is read from "/api/v2/status.json", and optionally the status of each component from "/api/v2/components.json".
+package statuspage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/nelkinda/health-go"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxBodySize limits the size of a Statuspage response which is read.
+const maxBodySize = 1 << 20
+
+// DefaultIndicators maps the indicators of the overall status of a Statuspage to a health.Status.
+var DefaultIndicators = map[string]health.Status{
+	"none":     health.Pass,
+	"minor":    health.Warn,
+	"major":    health.Warn,
+	"critical": health.Fail,
+}
+
+// DefaultComponentStatuses maps the statuses of Statuspage components to a health.Status.
+var DefaultComponentStatuses = map[string]health.Status{
+	"operational":          health.Pass,
+	"under_maintenance":    health.Warn,
+	"degraded_performance": health.Warn,
+	"partial_outage":       health.Warn,
+	"major_outage":         health.Fail,
+}
+
+type statusPage struct {
+	name              string
+	baseURL           string
+	client            *http.Client
+	timeout           time.Duration
+	indicators        map[string]health.Status
+	components        bool
+	componentStatuses map[string]health.Status
+}
+
+// Option configures a Statuspage health check.
+type Option func(*statusPage)
+
+// WithBaseURL sets the base URL of the Statuspage.
+func WithBaseURL(baseURL string) Option {
+	return func(s *statusPage) {
+		s.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithHTTPClient sets the http.Client for the requests to the Statuspage.
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *statusPage) {
+		s.client = client
+	}
+}
+
+// WithTimeout sets the timeout for the requests to the Statuspage.
+// The default is 2 seconds, and 0 means no timeout other than the deadline of the context.
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *statusPage) {
+		s.timeout = timeout
+	}
+}
+
+// WithIndicatorStatus maps an indicator of the overall status of the Statuspage to a health.Status, overriding DefaultIndicators.
+// Unknown indicators fail.
+func WithIndicatorStatus(indicator string, status health.Status) Option {
+	return func(s *statusPage) {
+		s.indicators[indicator] = status
+	}
+}
+
+// WithComponents additionally reports the status of each component of the Statuspage.
+// The componentId is the id of the component, because names are not unique, and the output starts with its name.
+func WithComponents() Option {
+	return func(s *statusPage) {
+		s.components = true
+	}
+}
+
+// WithComponentStatus maps the status of a Statuspage component to a health.Status, overriding DefaultComponentStatuses.
+// Unknown component statuses fail.
+func WithComponentStatus(componentStatus string, status health.Status) Option {
+	return func(s *statusPage) {
+		s.componentStatuses[componentStatus] = status
+	}
+}
+
+// statusResponse is the document served at /api/v2/status.json.
+type statusResponse struct {
+	Status *struct {
+		Indicator   string `json:"indicator"`
+		Description string `json:"description"`
+	} `json:"status"`
+}
+
+// componentsResponse is the document served at /api/v2/components.json.
+type componentsResponse struct {
+	Components []struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Status string `json:"status"`
+		Group  bool   `json:"group"`
+	} `json:"components"`
+}
+
+// get fetches a JSON document from the Statuspage.
+func (s *statusPage) get(ctx context.Context, path string, document interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not get status from %s: HTTP status code %d", s.name, res.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(res.Body, maxBodySize)).Decode(document); err != nil {
+		return fmt.Errorf("could not parse response from %s: %v", s.name, err)
+	}
+	return nil
+}
+
+func (s *statusPage) status(ctx context.Context) health.Checks {
+	var response statusResponse
+	if err := s.get(ctx, "/api/v2/status.json", &response); err != nil {
+		return health.Checks{Status: health.Fail, Output: err.Error()}
+	}
+	if response.Status == nil {
+		return health.Checks{Status: health.Fail, Output: fmt.Sprintf("could not parse response from %s: no status", s.name)}
+	}
+	if status, ok := s.indicators[response.Status.Indicator]; ok {
+		checks := health.Checks{Status: status}
+		if status != health.Pass {
+			checks.Output = response.Status.Description
+		}
+		return checks
+	}
+	if response.Status.Description == "" {
+		return health.Checks{Status: health.Fail, Output: fmt.Sprintf("could not get description from %s: unknown indicator %q", s.name, response.Status.Indicator)}
+	}
+	return health.Checks{Status: health.Fail, Output: response.Status.Description}
+}
+
+func (s *statusPage) componentChecks(ctx context.Context) []health.Checks {
+	var response componentsResponse
+	if err := s.get(ctx, "/api/v2/components.json", &response); err != nil {
+		return []health.Checks{{Status: health.Fail, Output: err.Error()}}
+	}
+	var checks []health.Checks
+	for _, component := range response.Components {
+		if component.Group {
+			continue
+		}
+		status, ok := s.componentStatuses[component.Status]
+		if !ok {
+			status = health.Fail
+		}
+		check := health.Checks{ComponentID: component.ID, ComponentType: "component", Status: status, Output: component.Name}
+		if status != health.Pass {
+			check.Output += ": " + component.Status
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+func (s *statusPage) HealthChecks() map[string][]health.Checks {
+	return s.HealthChecksContext(context.Background())
+}
+
+func (s *statusPage) HealthChecksContext(ctx context.Context) map[string][]health.Checks {
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	status := s.status(ctx)
+	status.Time = now
+	result := map[string][]health.Checks{s.name: {status}}
+	if s.components {
+		components := s.componentChecks(ctx)
+		for i := range components {
+			components[i].Time = now
+		}
+		result[s.name+":components"] = components
+	}
+	return result
+}
+
+func (*statusPage) AuthorizeHealth(*http.Request) bool {
+	return true
+}
+
+// Health returns a ChecksProvider for the Statuspage at baseURL, like "https://www.githubstatus.com".
+// The overall status is reported under the key name, the components, if enabled with WithComponents, under "<name>:components".
+func Health(name string, baseURL string, options ...Option) health.ChecksProvider {
+	s := &statusPage{
+		name:              name,
+		baseURL:           strings.TrimSuffix(baseURL, "/"),
+		client:            http.DefaultClient,
+		timeout:           2 * time.Second,
+		indicators:        make(map[string]health.Status),
+		componentStatuses: make(map[string]health.Status),
+	}
+	for indicator, status := range DefaultIndicators {
+		s.indicators[indicator] = status
+	}
+	for componentStatus, status := range DefaultComponentStatuses {
+		s.componentStatuses[componentStatus] = status
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
@@ -0,0 +1,128 @@
+package statuspage
+
+import (
+	"github.com/christianhujer/assert"
+	"github.com/nelkinda/health-go"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleComponents = `{
+  "page": {"id": "kctbh9vrtdwd", "name": "GitHub", "url": "https://www.githubstatus.com"},
+  "components": [
+    {"id": "8l4ygp009s5s", "name": "Git Operations", "status": "operational", "group": false},
+    {"id": "brv1bkgrwx7q", "name": "API Requests", "status": "degraded_performance", "group": false},
+    {"id": "4230lsnqdsld", "name": "Webhooks", "status": "major_outage", "group": false},
+    {"id": "0l2p9nhqnxpd", "name": "Visit www.githubstatus.com", "status": "operational", "group": true},
+    {"id": "h2ftsgbw7kmk", "name": "Pages", "status": "exploded", "group": false}
+  ]
+}`
+
+func newServer(status string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/status.json", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(status))
+	})
+	mux.HandleFunc("/api/v2/components.json", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(sampleComponents))
+	})
+	return httptest.NewServer(mux)
+}
+
+func statusOf(indicator string, description string) string {
+	return `{"page": {"name": "GitHub"}, "status": {"indicator": "` + indicator + `", "description": "` + description + `"}}`
+}
+
+func TestHealthIndicators(t *testing.T) {
+	for _, tc := range []struct {
+		indicator string
+		status    health.Status
+		output    string
+	}{
+		{"none", health.Pass, ""},
+		{"minor", health.Warn, "Minor Service Outage"},
+		{"major", health.Warn, "Minor Service Outage"},
+		{"critical", health.Fail, "Minor Service Outage"},
+		{"unknown", health.Fail, "Minor Service Outage"},
+	} {
+		server := newServer(statusOf(tc.indicator, "Minor Service Outage"))
+		checks := Health("GitHub", server.URL+"/").HealthChecks()
+		server.Close()
+		_ = assert.Equals(t, 1, len(checks))
+		_ = assert.Equals(t, tc.status, checks["GitHub"][0].Status)
+		_ = assert.Equals(t, tc.output, checks["GitHub"][0].Output)
+		_ = assert.True(t, checks["GitHub"][0].Time != "")
+	}
+}
+
+func TestHealthMalformed(t *testing.T) {
+	for body, output := range map[string]string{
+		`{"status": {"indicator": "unknown"}}`: `could not get description from GitHub: unknown indicator "unknown"`,
+		`{"page": {}}`:                         "could not parse response from GitHub: no status",
+		`not json`:                             "could not parse response from GitHub: invalid character 'o' in literal null (expecting 'u')",
+		`{"status": "none"}`:                   "could not parse response from GitHub: json: cannot unmarshal string",
+	} {
+		server := newServer(body)
+		checks := Health("GitHub", server.URL).HealthChecks()
+		server.Close()
+		_ = assert.Equals(t, health.Fail, checks["GitHub"][0].Status)
+		_ = assert.True(t, strings.HasPrefix(checks["GitHub"][0].Output, output))
+	}
+}
+
+func TestHealthUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	checks := Health("GitHub", server.URL, WithComponents()).HealthChecks()
+	_ = assert.Equals(t, "could not get status from GitHub: HTTP status code 404", checks["GitHub"][0].Output)
+	_ = assert.Equals(t, "could not get status from GitHub: HTTP status code 404", checks["GitHub:components"][0].Output)
+	server.Close()
+
+	checks = Health("GitHub", server.URL).HealthChecks()
+	_ = assert.Equals(t, health.Fail, checks["GitHub"][0].Status)
+
+	checks = Health("GitHub", "://invalid").HealthChecks()
+	_ = assert.Equals(t, health.Fail, checks["GitHub"][0].Status)
+}
+
+func TestHealthComponents(t *testing.T) {
+	server := newServer(statusOf("minor", "Partially Degraded Service"))
+	defer server.Close()
+	checks := Health("GitHub", server.URL, WithComponents()).HealthChecks()
+	components := checks["GitHub:components"]
+	_ = assert.Equals(t, 4, len(components))
+	for i, expected := range []health.Checks{
+		{ComponentID: "8l4ygp009s5s", ComponentType: "component", Status: health.Pass, Output: "Git Operations"},
+		{ComponentID: "brv1bkgrwx7q", ComponentType: "component", Status: health.Warn, Output: "API Requests: degraded_performance"},
+		{ComponentID: "4230lsnqdsld", ComponentType: "component", Status: health.Fail, Output: "Webhooks: major_outage"},
+		{ComponentID: "h2ftsgbw7kmk", ComponentType: "component", Status: health.Fail, Output: "Pages: exploded"},
+	} {
+		expected.Time = components[i].Time
+		_ = assert.DeepEquals(t, expected, components[i])
+	}
+}
+
+func TestHealthOptions(t *testing.T) {
+	server := newServer(statusOf("major", "Major Service Outage"))
+	defer server.Close()
+	checks := Health(
+		"GitHub",
+		"http://unused.example.com",
+		WithBaseURL(server.URL+"/"),
+		WithHTTPClient(server.Client()),
+		WithTimeout(time.Second),
+		WithIndicatorStatus("major", health.Fail),
+		WithComponents(),
+		WithComponentStatus("degraded_performance", health.Pass),
+	).HealthChecks()
+	_ = assert.Equals(t, health.Fail, checks["GitHub"][0].Status)
+	_ = assert.Equals(t, health.Pass, checks["GitHub:components"][1].Status)
+	_ = assert.Equals(t, health.Warn, DefaultIndicators["major"])
+	_ = assert.True(t, Health("GitHub", server.URL).AuthorizeHealth(nil))
+
+	checks = Health("GitHub", server.URL, WithTimeout(0)).HealthChecks()
+	_ = assert.Equals(t, health.Warn, checks["GitHub"][0].Status)
+	_ = assert.Equals(t, "Major Service Outage", checks["GitHub"][0].Output)
+}
@@ -1,7 +1,5 @@
 // Package sysinfo provides sysinfo as health checks.
-
-// +build !linux
-
+// The load and memory thresholds are optional; a threshold of 0 means no threshold.
 package sysinfo
 
 import (
@@ -10,19 +8,46 @@ import (
 )
 
 type sysinfo struct {
+	loadWarn       float64
+	loadFail       float64
+	freeMemoryWarn float64
+	freeMemoryFail float64
+}
+
+// Option configures a sysinfo health check.
+type Option func(*sysinfo)
+
+// WithLoadThresholds sets the load per CPU above which the "cpu:utilization" checks warn or fail.
+// A load per CPU of 1 means that on average, all CPUs were busy.
+func WithLoadThresholds(warn float64, fail float64) Option {
+	return func(s *sysinfo) {
+		s.loadWarn = warn
+		s.loadFail = fail
+	}
 }
 
-func (u *sysinfo) HealthChecks() map[string][]health.Checks {
-	return map[string][]health.Checks{}
+// WithFreeMemoryThresholds sets the available RAM in percent below which the "Used Ram" check warns or fails.
+func WithFreeMemoryThresholds(warn float64, fail float64) Option {
+	return func(s *sysinfo) {
+		s.freeMemoryWarn = warn
+		s.freeMemoryFail = fail
+	}
 }
 
-func (*sysinfo) AuthorizeHealth(r *http.Request) bool {
+func (*sysinfo) AuthorizeHealth(*http.Request) bool {
 	return true
 }
 
 // Health returns a ChecksProvider that provides sysinfo statistics.
 // On Linux, this will be checks from syscall.Sysinfo_t.
+// The load averages are reported per CPU, and the used RAM and swap in percent.
+// RAM counts as used unless it is available according to MemAvailable in /proc/meminfo, so the page cache does not count as used.
+// Without MemAvailable, which kernels before 3.14 lack, the free RAM and buffers count as available.
 // On other platforms, this provider provides no information.
-func Health() health.ChecksProvider {
-	return &sysinfo{}
+func Health(options ...Option) health.ChecksProvider {
+	s := &sysinfo{}
+	for _, option := range options {
+		option(s)
+	}
+	return s
 }
@@ -1,29 +1,68 @@
-// Package sysinfo provides sysinfo as health checks.
-
 // +build linux
 
 package sysinfo
 
 import (
+	"bufio"
 	"fmt"
 	"github.com/nelkinda/health-go"
-	"net/http"
+	"github.com/nelkinda/health-go/internal/threshold"
 	"os"
+	"runtime"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 )
 
-type sysinfo struct {
+// readSysinfo, numCPU, and meminfoPath are the sources of the checks, replaced in tests.
+var readSysinfo = syscall.Sysinfo
+var numCPU = runtime.NumCPU
+var meminfoPath = "/proc/meminfo"
+
+// readMemAvailable reads the memory available for starting new applications from /proc/meminfo, in bytes.
+// Unlike the free RAM of syscall.Sysinfo_t, it includes the page cache and other memory which the kernel can reclaim.
+func readMemAvailable(path string) (uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = file.Close() }()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 || fields[0] != "MemAvailable:" || fields[2] != "kB" {
+			continue
+		}
+		kilobytes, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("sysinfo: %s: %v", path, err)
+		}
+		return kilobytes * 1024, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("sysinfo: %s: no MemAvailable", path)
+}
+
+// percentage returns part of total in percent.
+func percentage(part uint64, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) * 100 / float64(total)
 }
 
 func (u *sysinfo) HealthChecks() map[string][]health.Checks {
 	si := &syscall.Sysinfo_t{}
-	err := syscall.Sysinfo(si)
+	err := readSysinfo(si)
 	now := time.Now().UTC().Format(time.RFC3339Nano)
 	var uptime func() health.Checks
 	var processes func() health.Checks
 	var cpuutil func(componentId string, load uint64) health.Checks
 	var memutil func(componentId string, load uint64) health.Checks
+	var memused func(componentId string, used uint64, total uint64, status health.Status) health.Checks
 	var hostname func() health.Checks
 	if err != nil {
 		cpuutil = func(componentId string, load uint64) health.Checks {
@@ -36,6 +75,9 @@ func (u *sysinfo) HealthChecks() map[string][]health.Checks {
 			}
 		}
 		memutil = cpuutil
+		memused = func(componentId string, _ uint64, _ uint64, _ health.Status) health.Checks {
+			return cpuutil(componentId, 0)
+		}
 		uptime = func() health.Checks {
 			return health.Checks{
 				ComponentType: "system",
@@ -47,13 +89,14 @@ func (u *sysinfo) HealthChecks() map[string][]health.Checks {
 		processes = uptime
 	} else {
 		memunit := fmt.Sprintf("%d bytes", si.Unit)
+		cpus := float64(numCPU())
 		cpuutil = func(componentId string, load uint64) health.Checks {
+			loadPerCPU := float64(load) / 65536.0 / cpus
 			return health.Checks{
 				ComponentType: "system",
 				ComponentID:   componentId,
-				ObservedValue: load / 65536.0,
-				ObservedUnit:  "%",
-				Status:        health.Pass,
+				ObservedValue: loadPerCPU,
+				Status:        threshold.Above(loadPerCPU, u.loadWarn, u.loadFail),
 				Time:          now,
 			}
 		}
@@ -67,6 +110,16 @@ func (u *sysinfo) HealthChecks() map[string][]health.Checks {
 				Time:          now,
 			}
 		}
+		memused = func(componentId string, used uint64, total uint64, status health.Status) health.Checks {
+			return health.Checks{
+				ComponentType: "system",
+				ComponentID:   componentId,
+				ObservedValue: percentage(used, total),
+				ObservedUnit:  "%",
+				Status:        status,
+				Time:          now,
+			}
+		}
 		uptime = func() health.Checks {
 			return health.Checks{
 				ComponentType: "system",
@@ -109,6 +162,19 @@ func (u *sysinfo) HealthChecks() map[string][]health.Checks {
 		}
 	}
 
+	// The fields of syscall.Sysinfo_t count in units of si.Unit bytes, and are only 32 bits wide on some platforms.
+	unit := uint64(si.Unit)
+	totalRam := uint64(si.Totalram) * unit
+	availableRam, meminfoErr := readMemAvailable(meminfoPath)
+	if meminfoErr != nil {
+		// Kernels before 3.14 do not estimate the available memory.
+		availableRam = (uint64(si.Freeram) + uint64(si.Bufferram)) * unit
+	}
+	if availableRam > totalRam {
+		availableRam = totalRam
+	}
+	ramStatus := threshold.Below(percentage(availableRam, totalRam), u.freeMemoryWarn, u.freeMemoryFail)
+
 	return map[string][]health.Checks{
 		"uptime": {
 			uptime(),
@@ -117,31 +183,22 @@ func (u *sysinfo) HealthChecks() map[string][]health.Checks {
 			hostname(),
 		},
 		"cpu:utilization": {
-			cpuutil("1 minute", si.Loads[0]),
-			cpuutil("5 minutes", si.Loads[1]),
-			cpuutil("15 minutes", si.Loads[2]),
+			cpuutil("1 minute", uint64(si.Loads[0])),
+			cpuutil("5 minutes", uint64(si.Loads[1])),
+			cpuutil("15 minutes", uint64(si.Loads[2])),
 			processes(),
 		},
 		"memory:utilization": {
-			memutil("Total Ram", si.Totalram),
-			memutil("Free Ram", si.Freeram),
-			memutil("Shared Ram", si.Sharedram),
-			memutil("Buffer Ram", si.Bufferram),
-			memutil("Total Swap", si.Totalswap),
-			memutil("Free Swap", si.Freeswap),
-			memutil("Total High", si.Totalhigh),
-			memutil("Free High", si.Freehigh),
+			memutil("Total Ram", uint64(si.Totalram)),
+			memutil("Free Ram", uint64(si.Freeram)),
+			memutil("Shared Ram", uint64(si.Sharedram)),
+			memutil("Buffer Ram", uint64(si.Bufferram)),
+			memutil("Total Swap", uint64(si.Totalswap)),
+			memutil("Free Swap", uint64(si.Freeswap)),
+			memutil("Total High", uint64(si.Totalhigh)),
+			memutil("Free High", uint64(si.Freehigh)),
+			memused("Used Ram", totalRam-availableRam, totalRam, ramStatus),
+			memused("Used Swap", uint64(si.Totalswap)-uint64(si.Freeswap), uint64(si.Totalswap), health.Pass),
 		},
 	}
 }
-
-func (*sysinfo) AuthorizeHealth(*http.Request) bool {
-	return true
-}
-
-// Health returns a ChecksProvider that provides sysinfo statistics.
-// On Linux, this will be checks from syscall.Sysinfo_t.
-// On other platforms, this provider provides no information.
-func Health() health.ChecksProvider {
-	return &sysinfo{}
-}
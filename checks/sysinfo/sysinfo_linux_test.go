@@ -0,0 +1,133 @@
+// +build linux
+
+package sysinfo
+
+import (
+	"errors"
+	"github.com/christianhujer/assert"
+	"github.com/nelkinda/health-go"
+	"io/ioutil"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func fakeSysinfo(t *testing.T, si syscall.Sysinfo_t, err error, cpus int) {
+	originalSysinfo, originalNumCPU := readSysinfo, numCPU
+	t.Cleanup(func() { readSysinfo, numCPU = originalSysinfo, originalNumCPU })
+	readSysinfo = func(info *syscall.Sysinfo_t) error {
+		*info = si
+		return err
+	}
+	numCPU = func() int { return cpus }
+	fakeMeminfo(t, "")
+}
+
+// fakeMeminfo replaces /proc/meminfo with the given content, or with a file which does not exist if content is empty.
+func fakeMeminfo(t *testing.T, content string) {
+	originalMeminfoPath := meminfoPath
+	t.Cleanup(func() { meminfoPath = originalMeminfoPath })
+	meminfoPath = "testdata/missing"
+	if content == "" {
+		return
+	}
+	file, err := ioutil.TempFile("", "meminfo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Remove(file.Name()) })
+	if _, err := file.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+	_ = file.Close()
+	meminfoPath = file.Name()
+}
+
+func find(checks []health.Checks, componentID string) health.Checks {
+	for _, check := range checks {
+		if check.ComponentID == componentID {
+			return check
+		}
+	}
+	return health.Checks{}
+}
+
+func TestLoadPerCPU(t *testing.T) {
+	si := syscall.Sysinfo_t{}
+	si.Loads[0] = 3 * 65536
+	si.Loads[1] = 65536 + 32768
+	si.Loads[2] = 65536 / 2
+	fakeSysinfo(t, si, nil, 2)
+
+	checks := Health(WithLoadThresholds(0.7, 1)).HealthChecks()["cpu:utilization"]
+
+	_ = assert.Equals(t, 1.5, find(checks, "1 minute").ObservedValue)
+	_ = assert.Equals(t, health.Fail, find(checks, "1 minute").Status)
+	_ = assert.Equals(t, 0.75, find(checks, "5 minutes").ObservedValue)
+	_ = assert.Equals(t, health.Warn, find(checks, "5 minutes").Status)
+	_ = assert.Equals(t, 0.25, find(checks, "15 minutes").ObservedValue)
+	_ = assert.Equals(t, health.Pass, find(checks, "15 minutes").Status)
+	_ = assert.Equals(t, "", find(checks, "15 minutes").ObservedUnit)
+}
+
+func TestUsedMemory(t *testing.T) {
+	for _, tc := range []struct {
+		available string
+		used      float64
+		expected  health.Status
+	}{
+		{"100", 50, health.Pass},
+		{"30", 85, health.Warn},
+		{"10", 95, health.Fail},
+		{"300", 0, health.Pass},
+	} {
+		// The free RAM is low, but most of the RAM is page cache, which is available.
+		fakeSysinfo(t, syscall.Sysinfo_t{Totalram: 200, Freeram: 1, Bufferram: 1, Totalswap: 100, Freeswap: 25, Unit: 1024}, nil, 1)
+		fakeMeminfo(t, "MemTotal:         200 kB\nMemFree:            1 kB\nMemAvailable:   "+tc.available+" kB\nBuffers:            1 kB\n")
+
+		checks := Health(WithFreeMemoryThresholds(20, 10)).HealthChecks()["memory:utilization"]
+
+		usedRam := find(checks, "Used Ram")
+		_ = assert.Equals(t, tc.used, usedRam.ObservedValue)
+		_ = assert.Equals(t, "%", usedRam.ObservedUnit)
+		_ = assert.Equals(t, tc.expected, usedRam.Status)
+		_ = assert.Equals(t, 75.0, find(checks, "Used Swap").ObservedValue)
+		_ = assert.Equals(t, uint64(200), find(checks, "Total Ram").ObservedValue)
+		_ = assert.Equals(t, "1024 bytes", find(checks, "Total Ram").ObservedUnit)
+	}
+}
+
+func TestUsedMemoryWithoutMemAvailable(t *testing.T) {
+	for _, meminfo := range []string{"", "MemTotal:         200 kB\n", "MemAvailable:   many kB\n"} {
+		fakeSysinfo(t, syscall.Sysinfo_t{Totalram: 200, Freeram: 40, Bufferram: 10, Unit: 1}, nil, 1)
+		fakeMeminfo(t, meminfo)
+
+		checks := Health(WithFreeMemoryThresholds(20, 10)).HealthChecks()["memory:utilization"]
+
+		_ = assert.Equals(t, 75.0, find(checks, "Used Ram").ObservedValue)
+		_ = assert.Equals(t, health.Pass, find(checks, "Used Ram").Status)
+	}
+}
+
+func TestNoSwap(t *testing.T) {
+	fakeSysinfo(t, syscall.Sysinfo_t{Totalram: 200, Freeram: 100, Unit: 1}, nil, 1)
+
+	checks := Health().HealthChecks()["memory:utilization"]
+
+	_ = assert.Equals(t, 0.0, find(checks, "Used Swap").ObservedValue)
+	_ = assert.Equals(t, health.Pass, find(checks, "Used Ram").Status)
+}
+
+func TestSysinfoError(t *testing.T) {
+	fakeSysinfo(t, syscall.Sysinfo_t{}, errors.New("sysinfo failed"), 1)
+
+	checks := Health().HealthChecks()
+
+	_ = assert.Equals(t, health.Fail, find(checks["cpu:utilization"], "1 minute").Status)
+	_ = assert.Equals(t, "sysinfo failed", find(checks["memory:utilization"], "Used Ram").Output)
+	_ = assert.Equals(t, health.Fail, checks["uptime"][0].Status)
+}
+
+func TestAuthorizeHealth(t *testing.T) {
+	_ = assert.True(t, Health().AuthorizeHealth(nil))
+}
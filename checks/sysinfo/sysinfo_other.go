@@ -0,0 +1,11 @@
+// +build !linux
+
+package sysinfo
+
+import (
+	"github.com/nelkinda/health-go"
+)
+
+func (u *sysinfo) HealthChecks() map[string][]health.Checks {
+	return map[string][]health.Checks{}
+}
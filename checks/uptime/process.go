@@ -6,6 +6,9 @@ import (
 	"time"
 )
 
+// initTime is the time the package was initialized, which is close to the process start.
+var initTime = time.Now().UTC()
+
 type process struct {
 	start time.Time
 }
@@ -22,6 +25,15 @@ func (u *process) HealthChecks() map[string][]health.Checks {
 				Time:          now,
 			},
 		},
+		"startTime": {
+			{
+				ComponentType: "process",
+				ObservedValue: u.start.Format(time.RFC3339),
+				ObservedUnit:  "RFC 3339",
+				Status:        health.Pass,
+				Time:          now,
+			},
+		},
 	}
 }
 
@@ -29,8 +41,14 @@ func (*process) AuthorizeHealth(*http.Request) bool {
 	return true
 }
 
-// Process returns a ChecksProvider for health checks about the process uptime.
-// Note that it does not really return the process uptime, but the time since calling this function.
+// Process returns a ChecksProvider for health checks about the process uptime under "uptime",
+// and the time the process started under "startTime".
+// On Linux, the start time is read from /proc.
+// On other platforms, or if /proc cannot be read, it is the time this package was initialized.
 func Process() health.ChecksProvider {
-	return &process{start: time.Now().UTC()}
+	start, err := processStart()
+	if err != nil {
+		start = initTime
+	}
+	return &process{start: start}
 }
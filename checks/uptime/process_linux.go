@@ -0,0 +1,68 @@
+// +build linux
+
+package uptime
+
+import (
+	"bufio"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// userHZ is the unit of times in /proc, in ticks per second.
+// It is 100 on all Linux platforms supported by Go.
+const userHZ = 100
+
+// procRoot is the path where procfs is mounted, replaced in tests.
+var procRoot = "/proc"
+
+// processStart returns the time the process started, from the start time in /proc/self/stat since the boot time in /proc/stat.
+func processStart() (time.Time, error) {
+	stat, err := ioutil.ReadFile(filepath.Join(procRoot, "self", "stat"))
+	if err != nil {
+		return time.Time{}, err
+	}
+	// The command in parentheses may contain spaces, so fields are counted from the closing parenthesis, after which the state is field 3.
+	closing := strings.LastIndexByte(string(stat), ')')
+	fields := strings.Fields(string(stat)[closing+1:])
+	if closing < 0 || len(fields) < 20 {
+		return time.Time{}, errors.New("uptime: malformed /proc/self/stat")
+	}
+	startTicks, err := strconv.ParseUint(fields[22-3], 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	bootTime, err := readBootTime()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return bootTime.Add(time.Duration(startTicks) * time.Second / userHZ).UTC(), nil
+}
+
+// readBootTime returns the boot time from the btime line of /proc/stat.
+func readBootTime() (time.Time, error) {
+	file, err := os.Open(filepath.Join(procRoot, "stat"))
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer func() { _ = file.Close() }()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "btime" {
+			seconds, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return time.Time{}, err
+			}
+			return time.Unix(seconds, 0), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return time.Time{}, err
+	}
+	return time.Time{}, errors.New("uptime: no btime in /proc/stat")
+}
@@ -0,0 +1,95 @@
+// +build linux
+
+package uptime
+
+import (
+	"github.com/christianhujer/assert"
+	"github.com/nelkinda/health-go"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func withProcRoot(t *testing.T, root string) {
+	original := procRoot
+	t.Cleanup(func() { procRoot = original })
+	procRoot = root
+}
+
+func TestProcessStart(t *testing.T) {
+	withProcRoot(t, "testdata/proc")
+
+	start, err := processStart()
+
+	_ = assert.Nil(t, err)
+	_ = assert.Equals(t, time.Date(2020, 3, 8, 16, 37, 37, 0, time.UTC).Add(2587910*time.Millisecond), start)
+}
+
+func TestProcessStartOfThisProcess(t *testing.T) {
+	start, err := processStart()
+
+	_ = assert.Nil(t, err)
+	_ = assert.True(t, !start.After(initTime.Add(time.Second)))
+	_ = assert.True(t, time.Since(start) < time.Hour)
+}
+
+func TestProcessStartErrors(t *testing.T) {
+	for _, tc := range []struct {
+		stat     string
+		procStat string
+		expected string
+	}{
+		{"1 (x) S 1", "btime 1\n", "uptime: malformed /proc/self/stat"},
+		{"1 x S 1 1 1 0 -1 0 0 0 0 0 0 0 0 0 20 0 1 0 100 0", "btime 1\n", "uptime: malformed /proc/self/stat"},
+		{"1 (x) S 1 1 1 0 -1 0 0 0 0 0 0 0 0 0 20 0 1 0 soon 0", "btime 1\n", `strconv.ParseUint: parsing "soon": invalid syntax`},
+		{"1 (x) S 1 1 1 0 -1 0 0 0 0 0 0 0 0 0 20 0 1 0 100 0", "cpu 1\n", "uptime: no btime in /proc/stat"},
+		{"1 (x) S 1 1 1 0 -1 0 0 0 0 0 0 0 0 0 20 0 1 0 100 0", "btime never\n", `strconv.ParseInt: parsing "never": invalid syntax`},
+	} {
+		root, err := ioutil.TempDir("", "proc")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = os.RemoveAll(root) }()
+		_ = os.Mkdir(filepath.Join(root, "self"), 0755)
+		_ = ioutil.WriteFile(filepath.Join(root, "self", "stat"), []byte(tc.stat), 0644)
+		_ = ioutil.WriteFile(filepath.Join(root, "stat"), []byte(tc.procStat), 0644)
+		withProcRoot(t, root)
+
+		_, err = processStart()
+
+		_ = assert.Equals(t, tc.expected, err.Error())
+	}
+}
+
+func TestProcessStartMissingFiles(t *testing.T) {
+	withProcRoot(t, "testdata/missing")
+	_, err := processStart()
+	_ = assert.NotNil(t, err)
+
+	withProcRoot(t, "testdata/proc/self")
+	_, err = readBootTime()
+	_ = assert.NotNil(t, err)
+}
+
+func TestProcessFallback(t *testing.T) {
+	withProcRoot(t, "testdata/missing")
+
+	checks := Process().HealthChecks()
+
+	_ = assert.Equals(t, initTime.Format(time.RFC3339), checks["startTime"][0].ObservedValue)
+	_ = assert.Equals(t, health.Pass, checks["uptime"][0].Status)
+	_ = assert.Equals(t, "process", checks["startTime"][0].ComponentType)
+}
+
+func TestProcess(t *testing.T) {
+	withProcRoot(t, "testdata/proc")
+
+	checks := Process().HealthChecks()
+
+	_ = assert.Equals(t, "2020-03-08T17:20:44Z", checks["startTime"][0].ObservedValue)
+	_ = assert.Equals(t, "RFC 3339", checks["startTime"][0].ObservedUnit)
+	_ = assert.True(t, checks["uptime"][0].ObservedValue.(float64) > 0)
+	_ = assert.True(t, Process().AuthorizeHealth(nil))
+}
@@ -0,0 +1,13 @@
+// +build !linux
+
+package uptime
+
+import (
+	"errors"
+	"time"
+)
+
+// processStart is not supported on platforms other than Linux.
+func processStart() (time.Time, error) {
+	return time.Time{}, errors.New("uptime: process start time not supported")
+}
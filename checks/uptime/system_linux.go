@@ -0,0 +1,44 @@
+// +build linux
+
+package uptime
+
+import (
+	"github.com/nelkinda/health-go"
+	"syscall"
+	"time"
+)
+
+// readSysinfo is the source of the system uptime, replaced in tests.
+var readSysinfo = syscall.Sysinfo
+
+func (u *system) HealthChecks() map[string][]health.Checks {
+	si := &syscall.Sysinfo_t{}
+	err := readSysinfo(si)
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	var uptime func() health.Checks
+	if err != nil {
+		uptime = func() health.Checks {
+			return health.Checks{
+				ComponentType: "system",
+				Status:        health.Fail,
+				Output:        err.Error(),
+				Time:          now,
+			}
+		}
+	} else {
+		uptime = func() health.Checks {
+			return health.Checks{
+				ComponentType: "system",
+				ObservedValue: si.Uptime,
+				ObservedUnit:  "s",
+				Status:        health.Pass,
+				Time:          now,
+			}
+		}
+	}
+	return map[string][]health.Checks{
+		"uptime": {
+			uptime(),
+		},
+	}
+}
@@ -0,0 +1,46 @@
+// +build linux
+
+package uptime
+
+import (
+	"errors"
+	"github.com/christianhujer/assert"
+	"github.com/nelkinda/health-go"
+	"syscall"
+	"testing"
+)
+
+// fakeSysinfo replaces readSysinfo with a function which fills in the info with set.
+// The fields of syscall.Sysinfo_t differ in size between architectures, so the test sets them itself.
+func fakeSysinfo(t *testing.T, set func(*syscall.Sysinfo_t), err error) {
+	original := readSysinfo
+	t.Cleanup(func() { readSysinfo = original })
+	readSysinfo = func(info *syscall.Sysinfo_t) error {
+		set(info)
+		return err
+	}
+}
+
+func TestSystem(t *testing.T) {
+	setUptime := func(info *syscall.Sysinfo_t) { info.Uptime = 15312 }
+	fakeSysinfo(t, setUptime, nil)
+	var expected syscall.Sysinfo_t
+	setUptime(&expected)
+
+	checks := System().HealthChecks()
+
+	_ = assert.Equals(t, expected.Uptime, checks["uptime"][0].ObservedValue)
+	_ = assert.Equals(t, "s", checks["uptime"][0].ObservedUnit)
+	_ = assert.Equals(t, "system", checks["uptime"][0].ComponentType)
+	_ = assert.Equals(t, health.Pass, checks["uptime"][0].Status)
+}
+
+func TestSystemError(t *testing.T) {
+	fakeSysinfo(t, func(*syscall.Sysinfo_t) {}, errors.New("sysinfo failed"))
+
+	checks := System().HealthChecks()
+
+	_ = assert.Equals(t, health.Fail, checks["uptime"][0].Status)
+	_ = assert.Equals(t, "sysinfo failed", checks["uptime"][0].Output)
+	_ = assert.True(t, System().AuthorizeHealth(nil))
+}
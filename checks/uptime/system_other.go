@@ -0,0 +1,11 @@
+// +build !linux
+
+package uptime
+
+import (
+	"github.com/nelkinda/health-go"
+)
+
+func (*system) HealthChecks() map[string][]health.Checks {
+	return map[string][]health.Checks{}
+}
@@ -0,0 +1,185 @@
+// Package client consumes health endpoints which implement the Health Check Response Format for HTTP APIs, like those served by health-go.
+//
+// Example:
+//
+//	response, err := client.Get(ctx, "http://localhost:8080/health")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Println(response.Status)
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/nelkinda/health-go"
+	"github.com/nelkinda/http-go/header"
+	"github.com/nelkinda/http-go/mimetype"
+	"io"
+	"net/http"
+	"time"
+)
+
+// maxBodySize limits the size of a health response which a Client reads.
+const maxBodySize = 1 << 20
+
+// Response is a health response received from a health endpoint.
+type Response struct {
+	health.Health
+
+	// StatusCode is the HTTP response code of the health endpoint.
+	StatusCode int
+}
+
+// SchemaError reports that a health response violates the Health Check Response Format.
+type SchemaError struct {
+	// Field is the path of the offending field, like "status" or "checks.mongodb:responseTime[0].status".
+	Field string
+
+	// Reason describes the violation.
+	Reason string
+}
+
+func (e *SchemaError) Error() string {
+	if e.Field == "" {
+		return "health response: " + e.Reason
+	}
+	return fmt.Sprintf("health response: %s: %s", e.Field, e.Reason)
+}
+
+// StatusCodeError reports that the HTTP response code of a health endpoint does not match the status of its response.
+// The RFC requires a code in the 2xx-3xx range for pass and warn, and in the 4xx-5xx range for fail.
+type StatusCodeError struct {
+	// StatusCode is the HTTP response code of the health endpoint.
+	StatusCode int
+
+	// Status is the status of the health response.
+	Status health.Status
+}
+
+func (e *StatusCodeError) Error() string {
+	return fmt.Sprintf("health response: HTTP status code %d does not match status %q", e.StatusCode, e.Status)
+}
+
+// Client fetches health responses from health endpoints.
+type Client struct {
+	httpClient *http.Client
+	header     http.Header
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the http.Client which a Client uses for requests.
+// The default is an http.Client with a timeout of 10 seconds.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithHeader adds a header to all requests of a Client, for example for authorization.
+func WithHeader(name string, value string) Option {
+	return func(c *Client) {
+		c.header.Add(name, value)
+	}
+}
+
+// New creates a new Client.
+func New(options ...Option) *Client {
+	c := &Client{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		header:     make(http.Header),
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// Get fetches, decodes and validates the health response of the health endpoint at url, using a default Client.
+func Get(ctx context.Context, url string) (*Response, error) {
+	return New().Get(ctx, url)
+}
+
+// Get fetches, decodes and validates the health response of the health endpoint at url.
+// The statuses in the response are normalized, see Validate.
+// If the response violates the format, the error is a *SchemaError.
+// If the HTTP response code does not match the status, the error is a *StatusCodeError, and the response is returned as well.
+func (c *Client) Get(ctx context.Context, url string) (*Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Do sends a request to a health endpoint, and decodes and validates its health response.
+// The headers of the Client are added to the request.
+// The errors are like those of Client.Get.
+func (c *Client) Do(req *http.Request) (*Response, error) {
+	for name, values := range c.header {
+		req.Header[name] = values
+	}
+	if req.Header.Get(header.Accept) == "" {
+		req.Header.Set(header.Accept, mimetype.ApplicationHealthJson+", "+mimetype.ApplicationJson+";q=0.9")
+	}
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = res.Body.Close() }()
+	return Decode(res.StatusCode, io.LimitReader(res.Body, maxBodySize))
+}
+
+// Decode decodes and validates a health response from body, received with the given HTTP response code.
+// The errors are like those of Client.Get.
+func Decode(statusCode int, body io.Reader) (*Response, error) {
+	response := &Response{StatusCode: statusCode}
+	if err := json.NewDecoder(body).Decode(&response.Health); err != nil {
+		return nil, &SchemaError{Reason: "invalid JSON: " + err.Error()}
+	}
+	if err := Validate(&response.Health); err != nil {
+		return nil, err
+	}
+	if !statusCodeMatches(statusCode, response.Status) {
+		return response, &StatusCodeError{StatusCode: statusCode, Status: response.Status}
+	}
+	return response, nil
+}
+
+// statusCodeMatches returns whether an HTTP response code is allowed for a status.
+func statusCodeMatches(statusCode int, status health.Status) bool {
+	if status == health.Fail {
+		return statusCode >= 400 && statusCode < 600
+	}
+	return statusCode >= 200 && statusCode < 400
+}
+
+// Validate checks a health response against the Health Check Response Format.
+// It normalizes the top-level status and the status of each check to Pass, Warn or Fail, accepting the aliases and any case, see health.ParseStatus.
+// If the response violates the format, the error is a *SchemaError.
+func Validate(h *health.Health) error {
+	if h.Status == "" {
+		return &SchemaError{Field: "status", Reason: "missing required field"}
+	}
+	status, err := health.ParseStatus(string(h.Status))
+	if err != nil {
+		return &SchemaError{Field: "status", Reason: err.Error()}
+	}
+	h.Status = status
+	for key, checks := range h.Checks {
+		for i := range checks {
+			if checks[i].Status == "" {
+				continue
+			}
+			status, err := health.ParseStatus(string(checks[i].Status))
+			if err != nil {
+				return &SchemaError{Field: fmt.Sprintf("checks.%s[%d].status", key, i), Reason: err.Error()}
+			}
+			checks[i].Status = status
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"github.com/christianhujer/assert"
+	"github.com/nelkinda/health-go"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type static map[string][]health.Checks
+
+func (s static) HealthChecks() map[string][]health.Checks {
+	return s
+}
+
+func (static) AuthorizeHealth(r *http.Request) bool {
+	return r.Header.Get("Authorization") == "Bearer secret"
+}
+
+func serve(statusCode int, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/health+json")
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+func TestGetFromHealthService(t *testing.T) {
+	service := health.New(
+		health.Health{Version: "1", ReleaseID: "1.0.0-SNAPSHOT"},
+		static{"mongodb:responseTime": {{ComponentID: "mongodb://127.0.0.1:27017", Status: health.Fail}}},
+	)
+	server := httptest.NewServer(http.HandlerFunc(service.Handler))
+	defer server.Close()
+
+	response, err := New(WithHeader("Authorization", "Bearer secret")).Get(context.Background(), server.URL)
+	_ = assert.Nil(t, err)
+	_ = assert.Equals(t, http.StatusServiceUnavailable, response.StatusCode)
+	_ = assert.Equals(t, health.Fail, response.Status)
+	_ = assert.Equals(t, "1.0.0-SNAPSHOT", response.ReleaseID)
+	_ = assert.Equals(t, "mongodb://127.0.0.1:27017", response.Checks["mongodb:responseTime"][0].ComponentID)
+
+	response, err = Get(context.Background(), server.URL)
+	_ = assert.Nil(t, err)
+	_ = assert.Equals(t, 0, len(response.Checks))
+}
+
+func TestGetNormalizesAliases(t *testing.T) {
+	server := serve(http.StatusOK, `{"status": "UP", "checks": {"db": [{"status": "ok"}, {"status": "Warn"}, {}]}}`)
+	defer server.Close()
+	response, err := New(WithHTTPClient(server.Client())).Get(context.Background(), server.URL)
+	_ = assert.Nil(t, err)
+	_ = assert.Equals(t, health.Pass, response.Status)
+	_ = assert.Equals(t, health.Pass, response.Checks["db"][0].Status)
+	_ = assert.Equals(t, health.Warn, response.Checks["db"][1].Status)
+	_ = assert.Equals(t, health.Status(""), response.Checks["db"][2].Status)
+
+	server = serve(http.StatusServiceUnavailable, `{"status": "down"}`)
+	defer server.Close()
+	response, err = Get(context.Background(), server.URL)
+	_ = assert.Nil(t, err)
+	_ = assert.Equals(t, health.Fail, response.Status)
+}
+
+func TestGetSchemaErrors(t *testing.T) {
+	for body, expected := range map[string]string{
+		`not json`:            "health response: invalid JSON: invalid character 'o' in literal null (expecting 'u')",
+		`{}`:                  "health response: status: missing required field",
+		`{"status": "green"}`: `health response: status: invalid status "green"`,
+		`{"status": "pass", "checks": {"db": [{"status": "red"}]}}`: `health response: checks.db[0].status: invalid status "red"`,
+	} {
+		server := serve(http.StatusOK, body)
+		response, err := Get(context.Background(), server.URL)
+		server.Close()
+		_ = assert.True(t, response == nil)
+		var schemaError *SchemaError
+		_ = assert.True(t, errors.As(err, &schemaError))
+		_ = assert.Equals(t, expected, err.Error())
+	}
+}
+
+func TestGetStatusCodeErrors(t *testing.T) {
+	for _, tc := range []struct {
+		statusCode int
+		body       string
+	}{
+		{http.StatusOK, `{"status": "fail"}`},
+		{http.StatusServiceUnavailable, `{"status": "pass"}`},
+		{http.StatusInternalServerError, `{"status": "warn"}`},
+		{http.StatusNotFound, `{"status": "pass"}`},
+	} {
+		server := serve(tc.statusCode, tc.body)
+		response, err := Get(context.Background(), server.URL)
+		server.Close()
+		var statusCodeError *StatusCodeError
+		_ = assert.True(t, errors.As(err, &statusCodeError))
+		_ = assert.Equals(t, tc.statusCode, statusCodeError.StatusCode)
+		_ = assert.Equals(t, tc.statusCode, response.StatusCode)
+		_ = assert.True(t, strings.Contains(err.Error(), "does not match status"))
+	}
+}
+
+func TestDoKeepsAccept(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status": "pass", "output": "` + r.Header.Get("Accept") + `"}`))
+	}))
+	defer server.Close()
+	response, err := New(WithHeader("Accept", "application/json")).Get(context.Background(), server.URL)
+	_ = assert.Nil(t, err)
+	_ = assert.Equals(t, "application/json", response.Output)
+}
+
+func TestGetRequestErrors(t *testing.T) {
+	_, err := Get(context.Background(), "://invalid")
+	_ = assert.NotNil(t, err)
+
+	server := serve(http.StatusOK, `{"status": "pass"}`)
+	server.Close()
+	_, err = Get(context.Background(), server.URL)
+	_ = assert.NotNil(t, err)
+}
@@ -0,0 +1,65 @@
+package health
+
+import (
+	"github.com/christianhujer/assert"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+type slowCheck struct {
+	key   string
+	delay time.Duration
+}
+
+func (s *slowCheck) HealthChecks() map[string][]Checks {
+	time.Sleep(s.delay)
+	return map[string][]Checks{s.key: {{ComponentID: s.key, Status: Pass}}}
+}
+
+func (*slowCheck) AuthorizeHealth(*http.Request) bool {
+	return true
+}
+
+func TestHandlerEvaluatesProvidersConcurrently(t *testing.T) {
+	delay := 100 * time.Millisecond
+	s := New(Health{}, &slowCheck{"a", delay}, &slowCheck{"b", delay}, &slowCheck{"c", delay})
+	start := time.Now()
+	_, h := serveHealth(t, s)
+	elapsed := time.Since(start)
+	_ = assert.Equals(t, 3, len(h.Checks))
+	_ = assert.True(t, elapsed < 3*delay)
+}
+
+func TestHandlerParallelRequests(t *testing.T) {
+	s := New(
+		Health{Version: "1", ReleaseID: "1.0.0-SNAPSHOT"},
+		SampleCheck(),
+		&slowCheck{"a", time.Millisecond},
+		staticCheck{"a": {{Status: Warn}}},
+		secretCheck{"hostname": {{Status: Pass}}},
+	)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w, h := serveHealth(t, s)
+			_ = assert.Equals(t, http.StatusOK, w.Code)
+			_ = assert.Equals(t, Warn, h.Status)
+			_ = assert.Equals(t, "1", h.Version)
+			_ = assert.Equals(t, 2, len(h.Checks["a"]))
+			_ = assert.Equals(t, 0, len(h.Checks["hostname"]))
+		}()
+	}
+	wg.Wait()
+	_ = assert.Equals(t, 0, len(s.template.Checks))
+}
+
+func TestHandlerKeepsProviderOrder(t *testing.T) {
+	s := New(Health{}, &slowCheck{"a", 20 * time.Millisecond}, staticCheck{"a": {{ComponentID: "b", Status: Pass}}})
+	_, h := serveHealth(t, s)
+	_ = assert.Equals(t, "a", h.Checks["a"][0].ComponentID)
+	_ = assert.Equals(t, "b", h.Checks["a"][1].ComponentID)
+}
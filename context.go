@@ -0,0 +1,117 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ContextChecksProvider provides health checks like ChecksProvider, but with a context.
+// The context carries the deadline and cancellation of the health request.
+type ContextChecksProvider interface {
+	// HealthChecksContext asks the ContextChecksProvider for its current Health status.
+	// Implementations should return as soon as possible after ctx is done.
+	HealthChecksContext(ctx context.Context) map[string][]Checks
+
+	// AuthorizeHealth asks whether the ContextChecksProvider authorizes Checks to be included in a Health response to this request.
+	AuthorizeHealth(r *http.Request) bool
+}
+
+// requestKey is the context key for the request for which checks are evaluated.
+type requestKey struct{}
+
+// RequestFromContext returns the health request for which a ContextChecksProvider is asked for its Checks.
+// It returns false if the Checks are not evaluated for a request, for example when they are refreshed in the background.
+func RequestFromContext(ctx context.Context) (*http.Request, bool) {
+	r, ok := ctx.Value(requestKey{}).(*http.Request)
+	return r, ok
+}
+
+type contextAdapter struct {
+	ChecksProvider
+}
+
+func (a *contextAdapter) HealthChecksContext(context.Context) map[string][]Checks {
+	return a.HealthChecks()
+}
+
+// AdaptChecksProvider returns a ContextChecksProvider for a ChecksProvider.
+// If checksProvider already implements ContextChecksProvider, it is returned unchanged.
+// Otherwise, the returned ContextChecksProvider ignores the context.
+func AdaptChecksProvider(checksProvider ChecksProvider) ContextChecksProvider {
+	if contextChecksProvider, ok := checksProvider.(ContextChecksProvider); ok {
+		return contextChecksProvider
+	}
+	return &contextAdapter{checksProvider}
+}
+
+// registration is a ContextChecksProvider registered with a Service.
+type registration struct {
+	// The provider of the checks.
+	checksProvider ContextChecksProvider
+	// The name of the provider, used for synthesized checks as long as no keys are known.
+	name string
+	// Guards keys.
+	mutex sync.Mutex
+	// The keys of the checks which the provider returned most recently.
+	keys []string
+	// The tags of the provider.
+	tags map[Tag]bool
+}
+
+func newRegistration(checksProvider interface{}, contextChecksProvider ContextChecksProvider) *registration {
+	return &registration{checksProvider: contextChecksProvider, name: fmt.Sprintf("%T", checksProvider), tags: make(map[Tag]bool)}
+}
+
+// healthChecks asks the provider for its checks.
+// If the provider does not return before ctx is done, it returns failed checks instead.
+func (reg *registration) healthChecks(ctx context.Context, timeout time.Duration) map[string][]Checks {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	done := make(chan map[string][]Checks, 1)
+	go func() {
+		done <- reg.checksProvider.HealthChecksContext(ctx)
+	}()
+	select {
+	case checks := <-done:
+		reg.rememberKeys(checks)
+		return checks
+	case <-ctx.Done():
+		output := ctx.Err().Error()
+		if timeout > 0 && ctx.Err() == context.DeadlineExceeded {
+			output = fmt.Sprintf("timeout after %v: %s", timeout, output)
+		}
+		return reg.failed(output)
+	}
+}
+
+func (reg *registration) rememberKeys(checks map[string][]Checks) {
+	keys := make([]string, 0, len(checks))
+	for key := range checks {
+		keys = append(keys, key)
+	}
+	reg.mutex.Lock()
+	defer reg.mutex.Unlock()
+	reg.keys = keys
+}
+
+// failed synthesizes failed checks for the keys which the provider returned most recently.
+func (reg *registration) failed(output string) map[string][]Checks {
+	reg.mutex.Lock()
+	keys := reg.keys
+	reg.mutex.Unlock()
+	if len(keys) == 0 {
+		keys = []string{reg.name}
+	}
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	checks := make(map[string][]Checks, len(keys))
+	for _, key := range keys {
+		checks[key] = []Checks{{Status: Fail, Output: output, Time: now}}
+	}
+	return checks
+}
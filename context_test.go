@@ -0,0 +1,115 @@
+package health
+
+import (
+	"context"
+	"github.com/christianhujer/assert"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type contextCheck struct {
+	delay time.Duration
+}
+
+func (c *contextCheck) HealthChecksContext(ctx context.Context) map[string][]Checks {
+	select {
+	case <-time.After(c.delay):
+		return map[string][]Checks{"context": {{Status: Pass}}}
+	case <-ctx.Done():
+		return map[string][]Checks{"context": {{Status: Fail, Output: ctx.Err().Error()}}}
+	}
+}
+
+func (*contextCheck) AuthorizeHealth(*http.Request) bool {
+	return true
+}
+
+type blockingCheck chan struct{}
+
+func (b blockingCheck) HealthChecks() map[string][]Checks {
+	<-b
+	return map[string][]Checks{"blocking": {{Status: Pass}}}
+}
+
+func (blockingCheck) AuthorizeHealth(*http.Request) bool {
+	return true
+}
+
+func TestAdaptChecksProvider(t *testing.T) {
+	contextChecksProvider := AdaptChecksProvider(SampleCheck())
+	_ = assert.Equals(t, Pass, contextChecksProvider.HealthChecksContext(context.Background())["sampleCheck"][0].Status)
+	_ = assert.True(t, AdaptChecksProvider(contextChecksProvider.(ChecksProvider)) == contextChecksProvider)
+}
+
+func TestContextChecksProvider(t *testing.T) {
+	_, h := serveHealth(t, NewWithOptions(Health{}, WithContextChecksProviders(&contextCheck{})))
+	_ = assert.Equals(t, Pass, h.Status)
+	_ = assert.Equals(t, Pass, h.Checks["context"][0].Status)
+}
+
+func TestTimeoutOfContextChecksProvider(t *testing.T) {
+	s := NewWithOptions(Health{}, WithContextChecksProviders(&contextCheck{time.Minute}), WithTimeout(10*time.Millisecond))
+	w, h := serveHealth(t, s)
+	_ = assert.Equals(t, http.StatusServiceUnavailable, w.Code)
+	_ = assert.Equals(t, Fail, h.Status)
+	_ = assert.Equals(t, 1, len(h.Checks))
+}
+
+func TestTimeoutOfChecksProvider(t *testing.T) {
+	block := make(blockingCheck)
+	defer close(block)
+	s := NewWithOptions(Health{}, WithChecksProviders(SampleCheck(), block), WithTimeout(10*time.Millisecond))
+	_, h := serveHealth(t, s)
+	_ = assert.Equals(t, Fail, h.Status)
+	_ = assert.Equals(t, Pass, h.Checks["sampleCheck"][0].Status)
+	timedOut := h.Checks["health.blockingCheck"]
+	_ = assert.Equals(t, 1, len(timedOut))
+	_ = assert.Equals(t, Fail, timedOut[0].Status)
+	_ = assert.True(t, strings.HasPrefix(timedOut[0].Output, "timeout after 10ms"))
+}
+
+func TestTimeoutUsesKnownKeys(t *testing.T) {
+	slow := &slowCheck{"slow", 0}
+	s := NewWithOptions(Health{}, WithChecksProviders(slow), WithTimeout(50*time.Millisecond))
+	_, h := serveHealth(t, s)
+	_ = assert.Equals(t, Pass, h.Checks["slow"][0].Status)
+	slow.delay = time.Second
+	_, h = serveHealth(t, s)
+	_ = assert.Equals(t, Fail, h.Checks["slow"][0].Status)
+}
+
+func TestCanceledRequest(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, "/health", nil)
+	if err != nil {
+		panic(err)
+	}
+	block := make(blockingCheck)
+	defer close(block)
+	s := New(Health{}, block)
+	h := s.evaluate(r, s.checksProviders)
+	_ = assert.Equals(t, context.Canceled.Error(), h.Checks["health.blockingCheck"][0].Output)
+}
+
+type requestCheck struct{}
+
+func (requestCheck) HealthChecksContext(ctx context.Context) map[string][]Checks {
+	r, ok := RequestFromContext(ctx)
+	if !ok {
+		return map[string][]Checks{"request": {{Status: Fail}}}
+	}
+	return map[string][]Checks{"request": {{ObservedValue: r.URL.Path, Status: Pass}}}
+}
+
+func (requestCheck) AuthorizeHealth(*http.Request) bool {
+	return true
+}
+
+func TestRequestFromContext(t *testing.T) {
+	_, h := serveHealth(t, NewWithOptions(Health{}, WithContextChecksProviders(requestCheck{})))
+	_ = assert.Equals(t, "/health", h.Checks["request"][0].ObservedValue)
+	_ = assert.Equals(t, Fail, requestCheck{}.HealthChecksContext(context.Background())["request"][0].Status)
+}
@@ -31,10 +31,14 @@
 package health
 
 import (
-	"encoding/json"
+	"context"
+	"fmt"
 	"github.com/nelkinda/http-go/header"
 	"github.com/nelkinda/http-go/mimetype"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 )
 
 // Status represents a health status.
@@ -203,6 +207,26 @@ const (
 	Warn Status = "warn"
 )
 
+// statusAliases maps the lowercase values and aliases which the RFC allows for the status field to their Status.
+var statusAliases = map[string]Status{
+	"pass":  Pass,
+	"ok":    Pass,
+	"up":    Pass,
+	"warn":  Warn,
+	"fail":  Fail,
+	"error": Fail,
+	"down":  Fail,
+}
+
+// ParseStatus parses the value of a status field.
+// As required by the RFC, the value is case-insensitive, and the aliases "ok" and "up" for Pass and "error" and "down" for Fail are accepted.
+func ParseStatus(value string) (Status, error) {
+	if status, ok := statusAliases[strings.ToLower(value)]; ok {
+		return status, nil
+	}
+	return "", fmt.Errorf("invalid status %q", value)
+}
+
 // ChecksProvider provides health checks, potentially with prior authorization.
 type ChecksProvider interface {
 	// HealthChecks asks the ChecksProvider for its current Health status.
@@ -215,42 +239,226 @@ type ChecksProvider interface {
 // Handler implements the health endpoint.
 // @Summary Service health
 // @Description Returns the service health according to the upcoming IETF RFC Health Check Response Format for HTTP APIs https://tools.ietf.org/id/draft-inadarei-api-health-check-02.html
-// @Produce application/json
+// @Produce application/health+json,application/json,text/plain,text/html
 // @Success 200 {object} health.Health
+// @Failure 503 {object} health.Health
 // @Router /health [GET]
 func (h *Service) Handler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Add(header.ContentType, mimetype.ApplicationHealthJson)
+	h.serve(w, r, h.checksProviders)
+}
+
+// serve implements the health endpoint for the given providers.
+// The media type of the response is negotiated from the Accept header of the request.
+func (h *Service) serve(w http.ResponseWriter, r *http.Request, checksProviders []*registration) {
+	w.Header().Add(header.Vary, header.Accept)
 	if r.Method == http.MethodOptions {
+		w.Header().Add(header.ContentType, mimetype.ApplicationHealthJson)
 		w.Header().Set("Allow", "OPTIONS, GET, HEAD")
 		w.Header().Set("Cache-Control", "max-age=604800")
 		w.WriteHeader(http.StatusOK)
 		return
 	}
 	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Add(header.ContentType, mimetype.ApplicationHealthJson)
 		w.WriteHeader(http.StatusMethodNotAllowed)
 		return
 	}
-	w.WriteHeader(http.StatusOK)
-	h.template.Status = Pass
-	h.template.Checks = make(map[string][]Checks)
-	for _, checksProvider := range h.checksProviders {
-		checksMap := checksProvider.HealthChecks()
-		for checksKey, checks := range checksMap {
-			h.template.Checks[checksKey] = append(h.template.Checks[checksKey], checks...)
+	selected, ok := negotiate(r.Header.Get(header.Accept))
+	if !ok {
+		w.Header().Set(header.ContentType, mimetype.TextPlain+"; charset=utf-8")
+		w.WriteHeader(http.StatusNotAcceptable)
+		_, _ = fmt.Fprintf(w, "Supported media types: %s\n", supportedMediaTypes())
+		return
+	}
+	w.Header().Add(header.ContentType, contentTypeHeaderValue(selected))
+	response := h.evaluate(r, checksProviders)
+	w.WriteHeader(h.statusCode(response.Status))
+	selected.render(w, response)
+}
+
+// providerResult is the outcome of asking one ChecksProvider for its Checks during one request.
+type providerResult struct {
+	checks     map[string][]Checks
+	authorized bool
+}
+
+// evaluate asks the given providers concurrently for their Checks and builds the response for the given request.
+// The response is a copy of the template, so concurrent requests do not interfere with each other.
+func (h *Service) evaluate(r *http.Request, checksProviders []*registration) Health {
+	ctx := context.WithValue(r.Context(), requestKey{}, r)
+	results := make([]providerResult, len(checksProviders))
+	var wg sync.WaitGroup
+	for i, checksProvider := range checksProviders {
+		wg.Add(1)
+		go func(i int, checksProvider *registration) {
+			defer wg.Done()
+			results[i] = providerResult{
+				checks:     checksProvider.healthChecks(ctx, h.timeout),
+				authorized: checksProvider.checksProvider.AuthorizeHealth(r),
+			}
+		}(i, checksProvider)
+	}
+	wg.Wait()
+
+	response := h.template
+	allChecks := make(map[string][]Checks)
+	response.Checks = make(map[string][]Checks)
+	for i, result := range results {
+		h.history.record(checksProviders[i], result.checks)
+		for checksKey, checks := range result.checks {
+			allChecks[checksKey] = append(allChecks[checksKey], checks...)
+			if result.authorized {
+				response.Checks[checksKey] = append(response.Checks[checksKey], checks...)
+			} else if h.unauthorized == Redact {
+				response.Checks[checksKey] = append(response.Checks[checksKey], redact(checks)...)
+			}
 		}
 	}
-	_ = json.NewEncoder(w).Encode(h.template)
+	response.Status = h.aggregator(allChecks)
+	return response
 }
 
 // Service describes an instance of a health service.
 type Service struct {
 	// The providers for checks of this health service.
-	checksProviders []ChecksProvider
+	checksProviders []*registration
 	// The template for the outer health response.
 	template Health
+	// The policy which computes the overall status from the checks.
+	aggregator Aggregator
+	// The HTTP response codes for each status.
+	statusCodes map[Status]int
+	// What to do with the checks of providers which do not authorize a request.
+	unauthorized Unauthorized
+	// The deadline for each provider, or 0 for none.
+	timeout time.Duration
+	// The interval for refreshing all providers in the background, or 0 for reading them on request.
+	pollInterval time.Duration
+	// The transitions of the statuses of the checks.
+	history *history
+}
+
+// Unauthorized determines how the Handler treats the Checks of a ChecksProvider which does not authorize a request.
+// Regardless of this setting, all Checks contribute to the overall Status.
+type Unauthorized int
+
+const (
+	// Drop omits the Checks of unauthorized ChecksProviders from the response.
+	Drop Unauthorized = iota
+
+	// Redact reduces the Checks of unauthorized ChecksProviders to their Status.
+	Redact
+)
+
+// redact returns copies of the given checks which only contain the status.
+func redact(checks []Checks) []Checks {
+	redacted := make([]Checks, len(checks))
+	for i, check := range checks {
+		redacted[i] = Checks{Status: check.Status}
+	}
+	return redacted
+}
+
+// statusCode returns the HTTP response code for the given status.
+func (h *Service) statusCode(status Status) int {
+	if code, ok := h.statusCodes[status]; ok {
+		return code
+	}
+	return h.statusCodes[Fail]
+}
+
+// Option configures optional behavior of a Service.
+type Option func(*Service)
+
+// WithChecksProviders adds providers for Checks to a Service.
+func WithChecksProviders(checksProviders ...ChecksProvider) Option {
+	return func(s *Service) {
+		for _, checksProvider := range checksProviders {
+			s.checksProviders = append(s.checksProviders, newRegistration(checksProvider, AdaptChecksProvider(checksProvider)))
+		}
+	}
+}
+
+// WithContextChecksProviders adds context-aware providers for Checks to a Service.
+func WithContextChecksProviders(checksProviders ...ContextChecksProvider) Option {
+	return func(s *Service) {
+		for _, checksProvider := range checksProviders {
+			s.checksProviders = append(s.checksProviders, newRegistration(checksProvider, checksProvider))
+		}
+	}
+}
+
+// WithTimeout sets the deadline for each provider.
+// A provider which does not return its Checks in time is reported with a failed Checks entry instead.
+// By default, providers are only limited by the context of the request.
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *Service) {
+		s.timeout = timeout
+	}
+}
+
+// WithAggregator sets the Aggregator which computes the overall Status of a Service from its Checks.
+// The default is Worst.
+func WithAggregator(aggregator Aggregator) Option {
+	return func(s *Service) {
+		s.aggregator = aggregator
+	}
+}
+
+// WithStatusCode sets the HTTP response code which the Handler uses for the given overall Status.
+// The defaults are 200 OK for Pass and Warn, and 503 Service Unavailable for Fail.
+// Note that the RFC requires a code in the 2xx-3xx range for Pass and Warn, and in the 4xx-5xx range for Fail.
+func WithStatusCode(status Status, code int) Option {
+	return func(s *Service) {
+		s.statusCodes[status] = code
+	}
+}
+
+// WithUnauthorized sets how the Handler treats the Checks of a ChecksProvider which does not authorize a request.
+// The default is Drop.
+func WithUnauthorized(unauthorized Unauthorized) Option {
+	return func(s *Service) {
+		s.unauthorized = unauthorized
+	}
+}
+
+// WithPollInterval makes the Service refresh all its providers in the background every interval, as if they were wrapped with Cached.
+// Requests are served from the most recent reading.
+// Refreshing runs between Service.Start and Service.Stop.
+// The timeout set with WithTimeout also applies to each background refresh.
+func WithPollInterval(interval time.Duration) Option {
+	return func(s *Service) {
+		s.pollInterval = interval
+	}
 }
 
 // New creates a new health service.
 func New(template Health, checksProviders ...ChecksProvider) *Service {
-	return &Service{checksProviders: checksProviders, template: template}
+	return NewWithOptions(template, WithChecksProviders(checksProviders...))
+}
+
+// NewWithOptions creates a new health service which is configured by the given options.
+func NewWithOptions(template Health, options ...Option) *Service {
+	s := &Service{
+		template:   template,
+		aggregator: Worst,
+		statusCodes: map[Status]int{
+			Pass: http.StatusOK,
+			Warn: http.StatusOK,
+			Fail: http.StatusServiceUnavailable,
+		},
+		history: newHistory(DefaultHistorySize),
+	}
+	for _, option := range options {
+		option(s)
+	}
+	if s.pollInterval > 0 {
+		for _, checksProvider := range s.checksProviders {
+			if _, ok := checksProvider.checksProvider.(Poller); !ok {
+				source := &registration{checksProvider: checksProvider.checksProvider, name: checksProvider.name}
+				checksProvider.checksProvider = newCached(source, s.pollInterval, s.timeout)
+			}
+		}
+	}
+	return s
 }
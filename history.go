@@ -0,0 +1,212 @@
+package health
+
+import (
+	"encoding/json"
+	"github.com/nelkinda/http-go/header"
+	"github.com/nelkinda/http-go/mimetype"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultHistorySize is the default number of Transitions which a Service keeps.
+const DefaultHistorySize = 100
+
+// Transition is a change of the Status of a check.
+type Transition struct {
+	// Key is the key of the check in the checks object.
+	Key string `json:"key"`
+
+	// ComponentID is the componentId of the check.
+	ComponentID string `json:"componentId,omitempty"`
+
+	// ComponentType is the componentType of the check.
+	ComponentType string `json:"componentType,omitempty"`
+
+	// From is the previous Status of the check, or empty if the check was seen for the first time.
+	From Status `json:"from,omitempty"`
+
+	// To is the new Status of the check.
+	To Status `json:"to"`
+
+	// Time is the date-time, in ISO8601 format, at which the transition was observed.
+	Time string `json:"time"`
+}
+
+// checkIdentity identifies a check of a provider across evaluations.
+type checkIdentity struct {
+	key           string
+	componentID   string
+	componentType string
+	// The number of checks with the same key, componentId and componentType before this one, to tell apart checks without componentId.
+	occurrence int
+}
+
+// recorded is a Transition together with the registration of the provider of its check.
+type recorded struct {
+	Transition
+	source *registration
+}
+
+// history records the Transitions of the checks of a Service in a ring buffer.
+type history struct {
+	// Guards all other fields.
+	mutex sync.Mutex
+	// The ring buffer of transitions.
+	transitions []recorded
+	// The index in transitions at which the next transition is recorded.
+	next int
+	// Whether the ring buffer has wrapped around.
+	full bool
+	// The most recent status of each check of each provider.
+	// Only the checks which a provider returned most recently are kept.
+	current map[*registration]map[checkIdentity]Status
+	// The callbacks which are notified about transitions.
+	subscribers map[int]func(Transition)
+	// The id of the next subscriber.
+	nextSubscriber int
+}
+
+func newHistory(size int) *history {
+	if size < 0 {
+		size = 0
+	}
+	return &history{
+		transitions: make([]recorded, size),
+		current:     make(map[*registration]map[checkIdentity]Status),
+		subscribers: make(map[int]func(Transition)),
+	}
+}
+
+// record compares the given checks of source with their previous statuses, records the transitions and notifies the subscribers.
+// Checks which source no longer returns are forgotten; if they return, they count as seen for the first time.
+func (hist *history) record(source *registration, checks map[string][]Checks) {
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	var transitions []Transition
+	var subscribers []func(Transition)
+	keys := make([]string, 0, len(checks))
+	for key := range checks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	hist.mutex.Lock()
+	previous := hist.current[source]
+	current := make(map[checkIdentity]Status)
+	hist.current[source] = current
+	for _, key := range keys {
+		for _, check := range checks[key] {
+			id := checkIdentity{key: key, componentID: check.ComponentID, componentType: check.ComponentType}
+			for _, taken := current[id]; taken; _, taken = current[id] {
+				id.occurrence++
+			}
+			current[id] = check.Status
+			from, seen := previous[id]
+			if seen && from == check.Status {
+				continue
+			}
+			transition := Transition{Key: key, ComponentID: check.ComponentID, ComponentType: check.ComponentType, From: from, To: check.Status, Time: now}
+			transitions = append(transitions, transition)
+			if len(hist.transitions) > 0 {
+				hist.transitions[hist.next] = recorded{Transition: transition, source: source}
+				hist.next = (hist.next + 1) % len(hist.transitions)
+				hist.full = hist.full || hist.next == 0
+			}
+		}
+	}
+	for _, subscriber := range hist.subscribers {
+		subscribers = append(subscribers, subscriber)
+	}
+	hist.mutex.Unlock()
+	for _, transition := range transitions {
+		for _, subscriber := range subscribers {
+			subscriber(transition)
+		}
+	}
+}
+
+// list returns the recorded transitions, oldest first.
+func (hist *history) list() []recorded {
+	hist.mutex.Lock()
+	defer hist.mutex.Unlock()
+	if !hist.full {
+		return append([]recorded{}, hist.transitions[:hist.next]...)
+	}
+	return append(append([]recorded{}, hist.transitions[hist.next:]...), hist.transitions[:hist.next]...)
+}
+
+func (hist *history) subscribe(callback func(Transition)) func() {
+	hist.mutex.Lock()
+	defer hist.mutex.Unlock()
+	id := hist.nextSubscriber
+	hist.nextSubscriber++
+	hist.subscribers[id] = callback
+	return func() {
+		hist.mutex.Lock()
+		defer hist.mutex.Unlock()
+		delete(hist.subscribers, id)
+	}
+}
+
+// WithHistorySize sets the number of Transitions which a Service keeps.
+// The default is DefaultHistorySize.
+// With size 0 or less, no Transitions are kept, but subscribers are still notified.
+func WithHistorySize(size int) Option {
+	return func(s *Service) {
+		s.history = newHistory(size)
+	}
+}
+
+// History returns the most recent Transitions of the checks of this Service, oldest first.
+// Transitions are observed whenever the checks are evaluated, for example by a request to Handler.
+func (h *Service) History() []Transition {
+	recordedTransitions := h.history.list()
+	transitions := make([]Transition, 0, len(recordedTransitions))
+	for _, transition := range recordedTransitions {
+		transitions = append(transitions, transition.Transition)
+	}
+	return transitions
+}
+
+// Subscribe registers a callback which is called for every Transition of the checks of this Service.
+// The callback is called synchronously during the evaluation of the checks, so it should return quickly.
+// The returned function cancels the subscription.
+func (h *Service) Subscribe(callback func(Transition)) (unsubscribe func()) {
+	return h.history.subscribe(callback)
+}
+
+// authorizedHistory returns the most recent Transitions which the request is authorized to see, oldest first.
+// Transitions of checks whose provider does not authorize the request are dropped or redacted, like the checks in Handler.
+func (h *Service) authorizedHistory(r *http.Request) []Transition {
+	authorized := make(map[*registration]bool)
+	transitions := make([]Transition, 0)
+	for _, transition := range h.history.list() {
+		isAuthorized, known := authorized[transition.source]
+		if !known {
+			isAuthorized = transition.source.checksProvider.AuthorizeHealth(r)
+			authorized[transition.source] = isAuthorized
+		}
+		switch {
+		case isAuthorized:
+			transitions = append(transitions, transition.Transition)
+		case h.unauthorized == Redact:
+			redacted := transition.Transition
+			redacted.ComponentID, redacted.ComponentType = "", ""
+			transitions = append(transitions, redacted)
+		}
+	}
+	return transitions
+}
+
+// HistoryHandler implements an endpoint, like /health/history, which returns the most recent Transitions as JSON array, oldest first.
+// Like in Handler, Transitions of checks whose provider does not authorize the request are dropped, or redacted with WithUnauthorized(Redact).
+func (h *Service) HistoryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set(header.Allow, "GET, HEAD")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set(header.ContentType, mimetype.ApplicationJson)
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(h.authorizedHistory(r))
+}
@@ -0,0 +1,29 @@
+// Package threshold determines the health.Status of observed values from warn and fail thresholds.
+// A threshold of 0 disables it.
+package threshold
+
+import "github.com/nelkinda/health-go"
+
+// Above returns the status for value, which is bad above warn and fail.
+func Above(value float64, warn float64, fail float64) health.Status {
+	switch {
+	case fail > 0 && value > fail:
+		return health.Fail
+	case warn > 0 && value > warn:
+		return health.Warn
+	default:
+		return health.Pass
+	}
+}
+
+// Below returns the status for value, which is bad below warn and fail.
+func Below(value float64, warn float64, fail float64) health.Status {
+	switch {
+	case fail > 0 && value < fail:
+		return health.Fail
+	case warn > 0 && value < warn:
+		return health.Warn
+	default:
+		return health.Pass
+	}
+}
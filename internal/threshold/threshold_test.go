@@ -0,0 +1,45 @@
+package threshold
+
+import (
+	"github.com/christianhujer/assert"
+	"github.com/nelkinda/health-go"
+	"testing"
+)
+
+func TestAbove(t *testing.T) {
+	for _, tc := range []struct {
+		value    float64
+		warn     float64
+		fail     float64
+		expected health.Status
+	}{
+		{50, 80, 90, health.Pass},
+		{80, 80, 90, health.Pass},
+		{85, 80, 90, health.Warn},
+		{95, 80, 90, health.Fail},
+		{95, 0, 90, health.Fail},
+		{95, 80, 0, health.Warn},
+		{95, 0, 0, health.Pass},
+	} {
+		_ = assert.Equals(t, tc.expected, Above(tc.value, tc.warn, tc.fail))
+	}
+}
+
+func TestBelow(t *testing.T) {
+	for _, tc := range []struct {
+		value    float64
+		warn     float64
+		fail     float64
+		expected health.Status
+	}{
+		{50, 20, 10, health.Pass},
+		{20, 20, 10, health.Pass},
+		{15, 20, 10, health.Warn},
+		{5, 20, 10, health.Fail},
+		{5, 0, 10, health.Fail},
+		{5, 20, 0, health.Warn},
+		{0, 0, 0, health.Pass},
+	} {
+		_ = assert.Equals(t, tc.expected, Below(tc.value, tc.warn, tc.fail))
+	}
+}
@@ -0,0 +1,40 @@
+// Package window tracks the increase of cumulative counters, like the number of OOM kills, within a sliding time window.
+// Unlike the increase since the previous reading, the increase within a window does not depend on how often, and by whom, the counter is read.
+package window
+
+import "time"
+
+type sample struct {
+	time  time.Time
+	value uint64
+}
+
+// Counter tracks the increase of a cumulative counter within a sliding time window.
+// A Counter is not safe for concurrent use.
+type Counter struct {
+	window time.Duration
+	// The readings at which the value changed, oldest first.
+	// The first one is the baseline, the value at the start of the window.
+	samples []sample
+}
+
+// NewCounter returns a Counter for the given window.
+func NewCounter(window time.Duration) *Counter {
+	return &Counter{window: window}
+}
+
+// Observe records the value of the counter read at now, and returns its increase within the window before now.
+// Until a window has passed since the first reading, the increase is relative to the first reading.
+// A counter which decreased, because it was reset, starts over.
+func (c *Counter) Observe(now time.Time, value uint64) uint64 {
+	if n := len(c.samples); n == 0 || value < c.samples[n-1].value {
+		c.samples = []sample{{time: now, value: value}}
+	} else if value > c.samples[n-1].value {
+		c.samples = append(c.samples, sample{time: now, value: value})
+	}
+	start := now.Add(-c.window)
+	for len(c.samples) > 1 && !c.samples[1].time.After(start) {
+		c.samples = c.samples[1:]
+	}
+	return value - c.samples[0].value
+}
@@ -0,0 +1,33 @@
+package window
+
+import (
+	"github.com/christianhujer/assert"
+	"testing"
+	"time"
+)
+
+func TestCounter(t *testing.T) {
+	start := time.Date(2020, 3, 8, 16, 37, 37, 0, time.UTC)
+	c := NewCounter(time.Minute)
+	for _, tc := range []struct {
+		after    time.Duration
+		value    uint64
+		expected uint64
+	}{
+		{0, 10, 0},
+		{10 * time.Second, 10, 0},
+		{20 * time.Second, 12, 2},
+		{30 * time.Second, 12, 2},
+		{30 * time.Second, 12, 2},
+		{50 * time.Second, 15, 5},
+		{70 * time.Second, 15, 5},
+		{80 * time.Second, 15, 3},
+		{100 * time.Second, 15, 3},
+		{110 * time.Second, 15, 0},
+		{120 * time.Second, 3, 0},
+		{130 * time.Second, 4, 1},
+		{200 * time.Second, 4, 0},
+	} {
+		_ = assert.Equals(t, tc.expected, c.Observe(start.Add(tc.after), tc.value))
+	}
+}
@@ -0,0 +1,187 @@
+package health
+
+import (
+	"fmt"
+	"github.com/nelkinda/http-go/header"
+	"io"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MimeTypeOpenMetrics is the media type of the OpenMetrics text format.
+const MimeTypeOpenMetrics = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// statuses are the possible values of Status, in the order in which they are exposed as metrics.
+var statuses = []Status{Pass, Warn, Fail}
+
+// reservedMetricNames are the names of the state sets, which a gauge of a check must not reuse.
+var reservedMetricNames = map[string]bool{"health_status": true, "health_check_status": true}
+
+// MetricsHandler implements an endpoint which exposes the Checks of all providers in the OpenMetrics text format, for example for Prometheus.
+// The overall status is exposed as state set health_status, the status of each check as state set health_check_status.
+// Each numeric observedValue is exposed as gauge named after the key of the check and its observedUnit, like health_mongodb_responseTime_ns.
+// The componentId and componentType of a check become labels.
+// Checks of the same key which would have the same labels are told apart by their position within the key, in label index.
+// A gauge which would be named like one of the state sets gets the suffix _value, like health_status_value.
+// Like in Worst, aliases count like the Status they stand for, and an unknown status counts as Warn.
+// Authorization applies like for Handler.
+func (h *Service) MetricsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set(header.Allow, "GET, HEAD")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	response := h.evaluate(r, h.checksProviders)
+	w.Header().Set(header.ContentType, MimeTypeOpenMetrics)
+	w.WriteHeader(http.StatusOK)
+	writeMetrics(w, response)
+}
+
+// metricSample is one line of a metric family.
+type metricSample struct {
+	labels string
+	value  string
+}
+
+// metricFamily is a group of samples with the same name.
+type metricFamily struct {
+	name    string
+	unit    string
+	samples []metricSample
+}
+
+func writeMetrics(w io.Writer, response Health) {
+	_, _ = fmt.Fprintln(w, "# TYPE health_status stateset")
+	_, _ = fmt.Fprintln(w, "# HELP health_status Overall health status of the service.")
+	for _, status := range statuses {
+		_, _ = fmt.Fprintf(w, "health_status{health_status=%q} %d\n", status, boolToInt(response.Status == status))
+	}
+
+	keys := make([]string, 0, len(response.Checks))
+	for key := range response.Checks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	_, _ = fmt.Fprintln(w, "# TYPE health_check_status stateset")
+	_, _ = fmt.Fprintln(w, "# HELP health_check_status Health status of a check.")
+	var families []*metricFamily
+	familiesByName := make(map[string]*metricFamily)
+	for _, key := range keys {
+		checks := response.Checks[key]
+		labelsList := make([]string, len(checks))
+		occurrences := make(map[string]int, len(checks))
+		for i, check := range checks {
+			labelsList[i] = checkLabels(key, check)
+			occurrences[labelsList[i]]++
+		}
+		for i, check := range checks {
+			labels := labelsList[i]
+			if occurrences[labels] > 1 {
+				labels += `,index="` + strconv.Itoa(i) + `"`
+			}
+			checkStatus, err := ParseStatus(string(check.Status))
+			if err != nil {
+				checkStatus = Warn
+			}
+			for _, status := range statuses {
+				_, _ = fmt.Fprintf(w, "health_check_status{%s,health_check_status=%q} %d\n", labels, status, boolToInt(checkStatus == status))
+			}
+			value, unit, ok := metricValue(check.ObservedValue, check.ObservedUnit)
+			if !ok {
+				continue
+			}
+			name := "health_" + sanitizeMetricName(key)
+			if unit != "" {
+				name += "_" + unit
+			}
+			if reservedMetricNames[name] {
+				name += "_value"
+			}
+			family, ok := familiesByName[name]
+			if !ok {
+				family = &metricFamily{name: name, unit: unit}
+				familiesByName[name] = family
+				families = append(families, family)
+			}
+			family.samples = append(family.samples, metricSample{labels: labels, value: value})
+		}
+	}
+
+	for _, family := range families {
+		_, _ = fmt.Fprintf(w, "# TYPE %s gauge\n", family.name)
+		if family.unit != "" {
+			_, _ = fmt.Fprintf(w, "# UNIT %s %s\n", family.name, family.unit)
+		}
+		for _, sample := range family.samples {
+			_, _ = fmt.Fprintf(w, "%s{%s} %s\n", family.name, sample.labels, sample.value)
+		}
+	}
+	_, _ = fmt.Fprintln(w, "# EOF")
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// checkLabels returns the labels which identify a check.
+func checkLabels(key string, check Checks) string {
+	labels := []string{`check="` + escapeLabelValue(key) + `"`}
+	if check.ComponentID != "" {
+		labels = append(labels, `component_id="`+escapeLabelValue(check.ComponentID)+`"`)
+	}
+	if check.ComponentType != "" {
+		labels = append(labels, `component_type="`+escapeLabelValue(check.ComponentType)+`"`)
+	}
+	return strings.Join(labels, ",")
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
+
+var invalidMetricNameCharacters = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+
+func sanitizeMetricName(name string) string {
+	return strings.Trim(invalidMetricNameCharacters.ReplaceAllString(name, "_"), "_")
+}
+
+// scaledUnit matches units with a factor, like "4096 bytes".
+var scaledUnit = regexp.MustCompile(`^(\d+) +(.+)$`)
+
+// metricValue formats a numeric observedValue and its unit for OpenMetrics.
+// Units with a factor, like "4096 bytes" from sysinfo, are resolved by scaling the value.
+// It returns false if the observedValue is not numeric.
+func metricValue(observedValue interface{}, observedUnit string) (string, string, bool) {
+	factor := uint64(1)
+	if match := scaledUnit.FindStringSubmatch(observedUnit); match != nil {
+		if f, err := strconv.ParseUint(match[1], 10, 64); err == nil {
+			factor = f
+			observedUnit = match[2]
+		}
+	}
+	unit := sanitizeMetricName(strings.Replace(observedUnit, "%", "percent", -1))
+	if observedValue == nil {
+		return "", "", false
+	}
+	v := reflect.ValueOf(observedValue)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int()*int64(factor), 10), unit, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint()*factor, 10), unit, true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float()*float64(factor), 'g', -1, 64), unit, true
+	default:
+		return "", "", false
+	}
+}
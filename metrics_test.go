@@ -0,0 +1,145 @@
+package health
+
+import (
+	"github.com/christianhujer/assert"
+	"github.com/nelkinda/http-go/header"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func serveMetrics(s *Service, method string) *httptest.ResponseRecorder {
+	r, err := http.NewRequest(method, "/metrics", nil)
+	if err != nil {
+		panic(err)
+	}
+	w := httptest.NewRecorder()
+	s.MetricsHandler(w, r)
+	return w
+}
+
+func TestMetricsHandler(t *testing.T) {
+	s := New(
+		Health{},
+		staticCheck{
+			"mongodb:responseTime": {{ComponentID: "mongodb://127.0.0.1:27017", ObservedValue: int64(147640), ObservedUnit: "ns", Status: Warn}},
+			"memory:utilization": {
+				{ComponentID: "Free Ram", ComponentType: "system", ObservedValue: uint64(1000), ObservedUnit: "4096 bytes", Status: Pass},
+				{ComponentID: "Used \"Ram\"", ComponentType: "system", ObservedValue: 12.5, ObservedUnit: "%", Status: Pass},
+			},
+			"cpu:utilization": {{ComponentID: "Processes", ComponentType: "system", ObservedValue: uint16(1449), Status: Pass}},
+			"hostname":        {{ComponentID: "hostname", ObservedValue: "localhost", Status: Pass}},
+			"SendGrid":        {{Status: Pass}},
+		},
+	)
+	w := serveMetrics(s, http.MethodGet)
+	_ = assert.Equals(t, http.StatusOK, w.Code)
+	_ = assert.Equals(t, MimeTypeOpenMetrics, w.Header().Get(header.ContentType))
+	_ = assert.Equals(t, `# TYPE health_status stateset
+# HELP health_status Overall health status of the service.
+health_status{health_status="pass"} 0
+health_status{health_status="warn"} 1
+health_status{health_status="fail"} 0
+# TYPE health_check_status stateset
+# HELP health_check_status Health status of a check.
+health_check_status{check="SendGrid",health_check_status="pass"} 1
+health_check_status{check="SendGrid",health_check_status="warn"} 0
+health_check_status{check="SendGrid",health_check_status="fail"} 0
+health_check_status{check="cpu:utilization",component_id="Processes",component_type="system",health_check_status="pass"} 1
+health_check_status{check="cpu:utilization",component_id="Processes",component_type="system",health_check_status="warn"} 0
+health_check_status{check="cpu:utilization",component_id="Processes",component_type="system",health_check_status="fail"} 0
+health_check_status{check="hostname",component_id="hostname",health_check_status="pass"} 1
+health_check_status{check="hostname",component_id="hostname",health_check_status="warn"} 0
+health_check_status{check="hostname",component_id="hostname",health_check_status="fail"} 0
+health_check_status{check="memory:utilization",component_id="Free Ram",component_type="system",health_check_status="pass"} 1
+health_check_status{check="memory:utilization",component_id="Free Ram",component_type="system",health_check_status="warn"} 0
+health_check_status{check="memory:utilization",component_id="Free Ram",component_type="system",health_check_status="fail"} 0
+health_check_status{check="memory:utilization",component_id="Used \"Ram\"",component_type="system",health_check_status="pass"} 1
+health_check_status{check="memory:utilization",component_id="Used \"Ram\"",component_type="system",health_check_status="warn"} 0
+health_check_status{check="memory:utilization",component_id="Used \"Ram\"",component_type="system",health_check_status="fail"} 0
+health_check_status{check="mongodb:responseTime",component_id="mongodb://127.0.0.1:27017",health_check_status="pass"} 0
+health_check_status{check="mongodb:responseTime",component_id="mongodb://127.0.0.1:27017",health_check_status="warn"} 1
+health_check_status{check="mongodb:responseTime",component_id="mongodb://127.0.0.1:27017",health_check_status="fail"} 0
+# TYPE health_cpu_utilization gauge
+health_cpu_utilization{check="cpu:utilization",component_id="Processes",component_type="system"} 1449
+# TYPE health_memory_utilization_bytes gauge
+# UNIT health_memory_utilization_bytes bytes
+health_memory_utilization_bytes{check="memory:utilization",component_id="Free Ram",component_type="system"} 4096000
+# TYPE health_memory_utilization_percent gauge
+# UNIT health_memory_utilization_percent percent
+health_memory_utilization_percent{check="memory:utilization",component_id="Used \"Ram\"",component_type="system"} 12.5
+# TYPE health_mongodb_responseTime_ns gauge
+# UNIT health_mongodb_responseTime_ns ns
+health_mongodb_responseTime_ns{check="mongodb:responseTime",component_id="mongodb://127.0.0.1:27017"} 147640
+# EOF
+`, w.Body.String())
+}
+
+func TestMetricsHandlerCollisions(t *testing.T) {
+	s := New(
+		Health{},
+		staticCheck{
+			"netstat:conntrack": {{Status: Fail, Output: "broken"}, {Status: "UP"}, {ComponentID: "count", Status: "garbage"}},
+			"status":            {{ObservedValue: 1, Status: Pass}},
+			"check:status":      {{ObservedValue: 2, Status: Pass}},
+		},
+	)
+	_ = assert.Equals(t, `# TYPE health_status stateset
+# HELP health_status Overall health status of the service.
+health_status{health_status="pass"} 0
+health_status{health_status="warn"} 0
+health_status{health_status="fail"} 1
+# TYPE health_check_status stateset
+# HELP health_check_status Health status of a check.
+health_check_status{check="check:status",health_check_status="pass"} 1
+health_check_status{check="check:status",health_check_status="warn"} 0
+health_check_status{check="check:status",health_check_status="fail"} 0
+health_check_status{check="netstat:conntrack",index="0",health_check_status="pass"} 0
+health_check_status{check="netstat:conntrack",index="0",health_check_status="warn"} 0
+health_check_status{check="netstat:conntrack",index="0",health_check_status="fail"} 1
+health_check_status{check="netstat:conntrack",index="1",health_check_status="pass"} 1
+health_check_status{check="netstat:conntrack",index="1",health_check_status="warn"} 0
+health_check_status{check="netstat:conntrack",index="1",health_check_status="fail"} 0
+health_check_status{check="netstat:conntrack",component_id="count",health_check_status="pass"} 0
+health_check_status{check="netstat:conntrack",component_id="count",health_check_status="warn"} 1
+health_check_status{check="netstat:conntrack",component_id="count",health_check_status="fail"} 0
+health_check_status{check="status",health_check_status="pass"} 1
+health_check_status{check="status",health_check_status="warn"} 0
+health_check_status{check="status",health_check_status="fail"} 0
+# TYPE health_check_status_value gauge
+health_check_status_value{check="check:status"} 2
+# TYPE health_status_value gauge
+health_status_value{check="status"} 1
+# EOF
+`, serveMetrics(s, http.MethodGet).Body.String())
+}
+
+func TestMetricsHandlerMethods(t *testing.T) {
+	s := New(Health{}, SampleCheck())
+	_ = assert.Equals(t, http.StatusOK, serveMetrics(s, http.MethodHead).Code)
+	_ = assert.Equals(t, http.StatusMethodNotAllowed, serveMetrics(s, http.MethodPost).Code)
+}
+
+func TestMetricValue(t *testing.T) {
+	for _, tc := range []struct {
+		observedValue interface{}
+		observedUnit  string
+		value         string
+		unit          string
+		ok            bool
+	}{
+		{nil, "s", "", "", false},
+		{"text", "", "", "", false},
+		{true, "", "", "", false},
+		{42, "s", "42", "s", true},
+		{int8(-3), "", "-3", "", true},
+		{float32(0.5), "load/cpu", "0.5", "load_cpu", true},
+		{uint32(2), "1 bytes", "2", "bytes", true},
+		{1.5, "99999999999999999999 bytes", "1.5", "99999999999999999999_bytes", true},
+	} {
+		value, unit, ok := metricValue(tc.observedValue, tc.observedUnit)
+		_ = assert.Equals(t, tc.value, value)
+		_ = assert.Equals(t, tc.unit, unit)
+		_ = assert.Equals(t, tc.ok, ok)
+	}
+}
@@ -0,0 +1,184 @@
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/nelkinda/http-go/mimetype"
+	"html/template"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// renderer writes a Health response in one media type.
+type renderer struct {
+	mediaType string
+	render    func(w io.Writer, response Health)
+}
+
+// renderers are the media types which the Handler supports, in order of preference.
+var renderers = []renderer{
+	{mimetype.ApplicationHealthJson, renderJSON},
+	{mimetype.ApplicationJson, renderJSON},
+	{mimetype.TextPlain, renderText},
+	{mimetype.TextHtml, renderHTML},
+}
+
+// mediaRange is one element of an Accept header.
+type mediaRange struct {
+	mediaType string
+	quality   float64
+}
+
+// parseAccept parses the media ranges and their qualities from the value of an Accept header.
+func parseAccept(accept string) []mediaRange {
+	var mediaRanges []mediaRange
+	for _, element := range strings.Split(accept, ",") {
+		parameters := strings.Split(element, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(parameters[0]))
+		if mediaType == "" {
+			continue
+		}
+		quality := 1.0
+		for _, parameter := range parameters[1:] {
+			nameValue := strings.SplitN(parameter, "=", 2)
+			if len(nameValue) == 2 && strings.TrimSpace(nameValue[0]) == "q" {
+				if q, err := strconv.ParseFloat(strings.TrimSpace(nameValue[1]), 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		mediaRanges = append(mediaRanges, mediaRange{mediaType: mediaType, quality: quality})
+	}
+	return mediaRanges
+}
+
+// specificity returns how specifically a media range matches a media type, or -1 if it does not match.
+func specificity(mediaRange string, mediaType string) int {
+	switch {
+	case mediaRange == mediaType:
+		return 2
+	case strings.HasSuffix(mediaRange, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(mediaRange, "*")):
+		return 1
+	case mediaRange == "*/*":
+		return 0
+	default:
+		return -1
+	}
+}
+
+// negotiate returns the renderer which best matches the given Accept header, or false if none is acceptable.
+// Without Accept header, the response is application/health+json.
+func negotiate(accept string) (renderer, bool) {
+	mediaRanges := parseAccept(accept)
+	if len(mediaRanges) == 0 {
+		return renderers[0], true
+	}
+	var best renderer
+	bestQuality := 0.0
+	for _, candidate := range renderers {
+		mostSpecific := -1
+		quality := 0.0
+		for _, mediaRange := range mediaRanges {
+			if s := specificity(mediaRange.mediaType, candidate.mediaType); s > mostSpecific {
+				mostSpecific = s
+				quality = mediaRange.quality
+			}
+		}
+		if quality > bestQuality {
+			best = candidate
+			bestQuality = quality
+		}
+	}
+	return best, bestQuality > 0
+}
+
+// supportedMediaTypes lists the media types which the Handler supports.
+func supportedMediaTypes() string {
+	mediaTypes := make([]string, len(renderers))
+	for i, r := range renderers {
+		mediaTypes[i] = r.mediaType
+	}
+	return strings.Join(mediaTypes, ", ")
+}
+
+func renderJSON(w io.Writer, response Health) {
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// sortedKeys returns the keys of the checks in a stable order.
+func sortedKeys(checks map[string][]Checks) []string {
+	keys := make([]string, 0, len(checks))
+	for key := range checks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// observation formats the observedValue and observedUnit of a check for humans.
+func observation(check Checks) string {
+	if check.ObservedValue == nil {
+		return ""
+	}
+	if check.ObservedUnit == "" {
+		return fmt.Sprint(check.ObservedValue)
+	}
+	return fmt.Sprintf("%v %s", check.ObservedValue, check.ObservedUnit)
+}
+
+// renderText writes the overall status in the first line, followed by one tab-separated line per check:
+// status, key, componentId, componentType, observed value with unit, and output.
+func renderText(w io.Writer, response Health) {
+	_, _ = fmt.Fprintln(w, response.Status)
+	for _, key := range sortedKeys(response.Checks) {
+		for _, check := range response.Checks[key] {
+			output := strings.Replace(check.Output, "\n", " ", -1)
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", check.Status, key, check.ComponentID, check.ComponentType, observation(check), output)
+		}
+	}
+}
+
+var htmlTemplate = template.Must(template.New("health").Funcs(template.FuncMap{
+	"observation": observation,
+	"sortedKeys":  sortedKeys,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Health: {{.Status}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; }
+.pass { background-color: #c8e6c9; }
+.warn { background-color: #ffe0b2; }
+.fail { background-color: #ffcdd2; }
+</style>
+</head>
+<body>
+<h1 class="{{.Status}}">{{with .ServiceID}}{{.}}: {{end}}{{.Status}}</h1>
+{{with .Description}}<p>{{.}}</p>
+{{end}}{{if or .Version .ReleaseID}}<p>Version {{.Version}} {{.ReleaseID}}</p>
+{{end}}{{range .Notes}}<p>{{.}}</p>
+{{end}}{{with .Output}}<pre>{{.}}</pre>
+{{end}}<table>
+<tr><th>Status</th><th>Check</th><th>Component ID</th><th>Component Type</th><th>Observed Value</th><th>Time</th><th>Output</th></tr>
+{{$checks := .Checks}}{{range $key := sortedKeys $checks}}{{range index $checks $key}}<tr class="{{.Status}}"><td>{{.Status}}</td><td>{{$key}}</td><td>{{.ComponentID}}</td><td>{{.ComponentType}}</td><td>{{observation .}}</td><td>{{.Time}}</td><td>{{.Output}}</td></tr>
+{{end}}{{end}}</table>
+</body>
+</html>
+`))
+
+func renderHTML(w io.Writer, response Health) {
+	_ = htmlTemplate.Execute(w, response)
+}
+
+// contentTypeHeaderValue returns the value of the Content-Type header for a renderer.
+func contentTypeHeaderValue(r renderer) string {
+	if strings.HasPrefix(r.mediaType, "text/") {
+		return r.mediaType + "; charset=utf-8"
+	}
+	return r.mediaType
+}
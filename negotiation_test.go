@@ -0,0 +1,92 @@
+package health
+
+import (
+	"github.com/christianhujer/assert"
+	"github.com/nelkinda/http-go/header"
+	"github.com/nelkinda/http-go/mimetype"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func serveAccept(s *Service, accept string) *httptest.ResponseRecorder {
+	r, err := http.NewRequest(http.MethodGet, "/health", nil)
+	if err != nil {
+		panic(err)
+	}
+	if accept != "" {
+		r.Header.Set(header.Accept, accept)
+	}
+	w := httptest.NewRecorder()
+	s.Handler(w, r)
+	return w
+}
+
+func TestNegotiate(t *testing.T) {
+	for _, tc := range []struct {
+		accept    string
+		mediaType string
+		ok        bool
+	}{
+		{"", mimetype.ApplicationHealthJson, true},
+		{"*/*", mimetype.ApplicationHealthJson, true},
+		{"application/*", mimetype.ApplicationHealthJson, true},
+		{"application/json", mimetype.ApplicationJson, true},
+		{"Text/Plain", mimetype.TextPlain, true},
+		{"text/*", mimetype.TextPlain, true},
+		{"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", mimetype.TextHtml, true},
+		{"application/json;q=0.5, text/plain", mimetype.TextPlain, true},
+		{"*/*;q=0.1, text/plain;q=0", mimetype.ApplicationHealthJson, true},
+		{"text/plain; charset=utf-8; q=invalid", mimetype.TextPlain, true},
+		{"application/xml", "", false},
+		{"*/*;q=0", "", false},
+	} {
+		selected, ok := negotiate(tc.accept)
+		_ = assert.Equals(t, tc.ok, ok)
+		_ = assert.Equals(t, tc.mediaType, selected.mediaType)
+	}
+}
+
+func TestHandlerJSON(t *testing.T) {
+	w := serveAccept(New(Health{}, SampleCheck()), "application/json")
+	_ = assert.Equals(t, mimetype.ApplicationJson, w.Header().Get(header.ContentType))
+	_ = assert.Equals(t, header.Accept, w.Header().Get(header.Vary))
+	_ = assert.True(t, strings.HasPrefix(w.Body.String(), `{"status":"pass"`))
+}
+
+func TestHandlerText(t *testing.T) {
+	s := New(Health{}, staticCheck{
+		"mongodb:responseTime": {{ComponentID: "mongodb://127.0.0.1:27017", ObservedValue: 147640, ObservedUnit: "ns", Status: Pass}},
+		"SendGrid":             {{Status: Fail, Output: "Major\noutage"}},
+		"cpu:utilization":      {{ComponentID: "Processes", ComponentType: "system", ObservedValue: 1449, Status: Pass}},
+	})
+	w := serveAccept(s, "text/plain")
+	_ = assert.Equals(t, http.StatusServiceUnavailable, w.Code)
+	_ = assert.Equals(t, "text/plain; charset=utf-8", w.Header().Get(header.ContentType))
+	_ = assert.Equals(t, "fail\n"+
+		"fail\tSendGrid\t\t\t\tMajor outage\n"+
+		"pass\tcpu:utilization\tProcesses\tsystem\t1449\t\n"+
+		"pass\tmongodb:responseTime\tmongodb://127.0.0.1:27017\t\t147640 ns\t\n", w.Body.String())
+}
+
+func TestHandlerHTML(t *testing.T) {
+	s := New(
+		Health{ServiceID: "example", Description: "<Example>", Version: "1", Notes: []string{"note"}, Output: "output"},
+		staticCheck{"SendGrid": {{Status: Warn, Output: "<minor>"}}},
+	)
+	w := serveAccept(s, "text/html")
+	_ = assert.Equals(t, http.StatusOK, w.Code)
+	_ = assert.Equals(t, "text/html; charset=utf-8", w.Header().Get(header.ContentType))
+	body := w.Body.String()
+	_ = assert.True(t, strings.Contains(body, `<h1 class="warn">example: warn</h1>`))
+	_ = assert.True(t, strings.Contains(body, `<tr class="warn"><td>warn</td><td>SendGrid</td>`))
+	_ = assert.True(t, strings.Contains(body, `&lt;minor&gt;`))
+	_ = assert.True(t, strings.Contains(body, `&lt;Example&gt;`))
+}
+
+func TestHandlerNotAcceptable(t *testing.T) {
+	w := serveAccept(New(Health{}, SampleCheck()), "application/xml")
+	_ = assert.Equals(t, http.StatusNotAcceptable, w.Code)
+	_ = assert.True(t, strings.Contains(w.Body.String(), mimetype.ApplicationHealthJson))
+}
@@ -0,0 +1,34 @@
+package health
+
+import (
+	"github.com/christianhujer/assert"
+	"net/http"
+	"testing"
+)
+
+func TestHandlerStatusCodes(t *testing.T) {
+	warning := staticCheck{"SendGrid": {{Status: Warn}}}
+	failing := staticCheck{"mongodb:responseTime": {{Status: Fail}}}
+
+	w, _ := serveHealth(t, New(Health{}, SampleCheck()))
+	_ = assert.Equals(t, http.StatusOK, w.Code)
+
+	w, _ = serveHealth(t, New(Health{}, warning))
+	_ = assert.Equals(t, http.StatusOK, w.Code)
+
+	w, h := serveHealth(t, New(Health{}, failing))
+	_ = assert.Equals(t, http.StatusServiceUnavailable, w.Code)
+	_ = assert.Equals(t, Fail, h.Status)
+
+	w, _ = serveHealth(t, NewWithOptions(Health{}, WithChecksProviders(warning), WithStatusCode(Warn, http.StatusTooManyRequests)))
+	_ = assert.Equals(t, http.StatusTooManyRequests, w.Code)
+
+	w, _ = serveHealth(t, NewWithOptions(Health{}, WithChecksProviders(failing), WithStatusCode(Fail, http.StatusInternalServerError)))
+	_ = assert.Equals(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestHandlerStatusCodeOfUnknownStatus(t *testing.T) {
+	unknown := func(map[string][]Checks) Status { return "unknown" }
+	w, _ := serveHealth(t, NewWithOptions(Health{}, WithAggregator(unknown)))
+	_ = assert.Equals(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,74 @@
+package health
+
+import "net/http"
+
+// Tag classifies ChecksProviders, so that a handler can evaluate a subset of them.
+type Tag string
+
+const (
+	// Liveness tags providers which tell whether the service is alive or needs to be restarted.
+	Liveness Tag = "liveness"
+
+	// Readiness tags providers which tell whether the service is ready to receive requests.
+	Readiness Tag = "readiness"
+
+	// Startup tags providers which tell whether the service has started.
+	Startup Tag = "startup"
+)
+
+// Tagged tags all providers which option adds to a Service with the given tags.
+//
+// Example:
+//
+//	h := health.NewWithOptions(
+//		health.Health{Version: "1", ReleaseID: "1.0.0-SNAPSHOT"},
+//		health.Tagged(health.WithChecksProviders(uptime.Process()), health.Liveness),
+//		health.Tagged(health.WithChecksProviders(mongodb.Health(url, client, timeout, threshold)), health.Readiness, health.Startup),
+//	)
+func Tagged(option Option, tags ...Tag) Option {
+	return func(s *Service) {
+		registered := len(s.checksProviders)
+		option(s)
+		for _, checksProvider := range s.checksProviders[registered:] {
+			for _, tag := range tags {
+				checksProvider.tags[tag] = true
+			}
+		}
+	}
+}
+
+// tagged returns the providers which have any of the given tags.
+func (h *Service) tagged(tags []Tag) []*registration {
+	var checksProviders []*registration
+	for _, checksProvider := range h.checksProviders {
+		for _, tag := range tags {
+			if checksProvider.tags[tag] {
+				checksProviders = append(checksProviders, checksProvider)
+				break
+			}
+		}
+	}
+	return checksProviders
+}
+
+// TagHandler returns a handler which implements a health endpoint for the providers which have any of the given tags.
+func (h *Service) TagHandler(tags ...Tag) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.serve(w, r, h.tagged(tags))
+	}
+}
+
+// LivenessHandler implements a liveness endpoint, like /livez, for the providers tagged with Liveness.
+func (h *Service) LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	h.TagHandler(Liveness)(w, r)
+}
+
+// ReadinessHandler implements a readiness endpoint, like /readyz, for the providers tagged with Readiness.
+func (h *Service) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	h.TagHandler(Readiness)(w, r)
+}
+
+// StartupHandler implements a startup endpoint, like /startupz, for the providers tagged with Startup.
+func (h *Service) StartupHandler(w http.ResponseWriter, r *http.Request) {
+	h.TagHandler(Startup)(w, r)
+}
@@ -0,0 +1,46 @@
+package health
+
+import (
+	"github.com/christianhujer/assert"
+	"net/http"
+	"testing"
+)
+
+func TestTaggedHandlers(t *testing.T) {
+	s := NewWithOptions(
+		Health{},
+		WithChecksProviders(staticCheck{"untagged": {{Status: Warn}}}),
+		Tagged(WithChecksProviders(staticCheck{"uptime": {{Status: Pass}}}), Liveness),
+		Tagged(WithChecksProviders(staticCheck{"mongodb:responseTime": {{Status: Fail}}}), Readiness, Startup),
+		Tagged(WithContextChecksProviders(&contextCheck{}), Readiness, "custom"),
+	)
+
+	w, h := serveHandler(t, s.LivenessHandler)
+	_ = assert.Equals(t, http.StatusOK, w.Code)
+	_ = assert.Equals(t, Pass, h.Status)
+	_ = assert.Equals(t, 1, len(h.Checks))
+
+	w, h = serveHandler(t, s.ReadinessHandler)
+	_ = assert.Equals(t, http.StatusServiceUnavailable, w.Code)
+	_ = assert.Equals(t, 2, len(h.Checks))
+
+	_, h = serveHandler(t, s.StartupHandler)
+	_ = assert.Equals(t, 1, len(h.Checks["mongodb:responseTime"]))
+
+	_, h = serveHandler(t, s.TagHandler("custom"))
+	_ = assert.Equals(t, 1, len(h.Checks["context"]))
+
+	_, h = serveHandler(t, s.TagHandler(Liveness, "custom"))
+	_ = assert.Equals(t, 2, len(h.Checks))
+
+	_, h = serveHandler(t, s.Handler)
+	_ = assert.Equals(t, Fail, h.Status)
+	_ = assert.Equals(t, 4, len(h.Checks))
+}
+
+func TestTagHandlerWithoutProviders(t *testing.T) {
+	w, h := serveHandler(t, New(Health{}, SampleCheck()).StartupHandler)
+	_ = assert.Equals(t, http.StatusOK, w.Code)
+	_ = assert.Equals(t, Pass, h.Status)
+	_ = assert.Equals(t, 0, len(h.Checks))
+}